@@ -0,0 +1,64 @@
+package requestgen
+
+import (
+	"errors"
+	"net/http"
+	"reflect"
+)
+
+// APIErrorReporter can be implemented by the error envelope type to report the failure
+// carried by a successful (2xx) response, e.g. {"code": "400100", "msg": "insufficient balance"}
+type APIErrorReporter interface {
+	IsError() bool
+}
+
+// DecodeAPIError decodes the response body into the error envelope apiErr, which must be a pointer.
+// It returns an *ErrResponse that wraps apiErr if the response has an error status code,
+// or if apiErr implements APIErrorReporter and reports a failure. Otherwise, nil is returned.
+func DecodeAPIError(req *http.Request, response *Response, apiErr error) error {
+	reporter, isReporter := apiErr.(APIErrorReporter)
+	if !response.IsError() && !isReporter {
+		return nil
+	}
+
+	if err := response.DecodeJSON(apiErr); err != nil {
+		if response.IsError() {
+			return &ErrResponse{Response: response, Body: response.Body, Request: req}
+		}
+
+		// the successful response is not an error envelope
+		return nil
+	}
+
+	if !response.IsError() && !reporter.IsError() {
+		return nil
+	}
+
+	return &ErrResponse{Response: response, Body: response.Body, Request: req, APIError: apiErr}
+}
+
+// WrapAPIError decodes the body of the *ErrResponse error into the error envelope apiErr,
+// so that the envelope can be extracted with errors.As. Other errors are returned as is.
+func WrapAPIError(err error, apiErr error) error {
+	var errResponse *ErrResponse
+	if !errors.As(err, &errResponse) || errResponse.APIError != nil || errResponse.Response == nil {
+		return err
+	}
+
+	if errResponse.Response.DecodeJSON(apiErr) == nil {
+		errResponse.APIError = apiErr
+	}
+
+	return err
+}
+
+// newAPIError allocates a new error envelope with the type of the given prototype
+func newAPIError(prototype error) (error, bool) {
+	rt := reflect.TypeOf(prototype)
+	if rt.Kind() != reflect.Ptr {
+		return nil, false
+	}
+
+	apiErr, ok := reflect.New(rt.Elem()).Interface().(error)
+	return apiErr, ok
+}
@@ -0,0 +1,58 @@
+package requestgen
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testAPIError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *testAPIError) Error() string {
+	return e.Message
+}
+
+func (e *testAPIError) IsError() bool {
+	return e.Code != 0
+}
+
+func TestBaseAPIClient_ErrorType(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/bad-request":
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"code":1001,"message":"invalid symbol"}`))
+		case "/envelope-error":
+			w.Write([]byte(`{"code":1002,"message":"insufficient balance"}`))
+		default:
+			w.Write([]byte(`{"code":0}`))
+		}
+	})
+	client.ErrorType = &testAPIError{}
+
+	ctx := context.Background()
+
+	for path, code := range map[string]int{"/bad-request": 1001, "/envelope-error": 1002} {
+		req, err := client.NewRequest(ctx, "GET", path, nil, nil)
+		assert.NoError(t, err)
+
+		_, err = client.SendRequest(req)
+
+		var apiErr *testAPIError
+		if assert.True(t, errors.As(err, &apiErr), path) {
+			assert.Equal(t, code, apiErr.Code)
+		}
+	}
+
+	req, err := client.NewRequest(ctx, "GET", "/ok", nil, nil)
+	assert.NoError(t, err)
+
+	_, err = client.SendRequest(req)
+	assert.NoError(t, err)
+}
@@ -0,0 +1,247 @@
+package requestgen
+
+import (
+	"container/list"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultCacheCapacity is the capacity of the LRUCache created with a non-positive capacity
+const DefaultCacheCapacity = 1024
+
+// CacheEntry is a cached response
+type CacheEntry struct {
+	Response *Response
+
+	// Expires is when the cached response becomes stale, the stale response is revalidated with
+	// its ETag or Last-Modified header before it's used again.
+	Expires time.Time
+}
+
+// Fresh reports whether the cached response can be used without asking the API server
+func (e *CacheEntry) Fresh(now time.Time) bool {
+	return now.Before(e.Expires)
+}
+
+// ResponseCache stores the cacheable responses of the GET requests, see BaseAPIClient.ResponseCache.
+// The cached responses are shared by the callers, so they must not be modified.
+type ResponseCache interface {
+	Get(key string) (*CacheEntry, bool)
+	Set(key string, entry *CacheEntry)
+	Delete(key string)
+}
+
+// LRUCache is an in-memory ResponseCache that evicts the least recently used entry when it's full
+type LRUCache struct {
+	capacity int
+
+	mu    sync.Mutex
+	ll    *list.List
+	items map[string]*list.Element
+}
+
+type lruItem struct {
+	key   string
+	entry *CacheEntry
+}
+
+// NewLRUCache creates an LRUCache that holds at most capacity entries, DefaultCacheCapacity is used if it's not positive.
+func NewLRUCache(capacity int) *LRUCache {
+	if capacity <= 0 {
+		capacity = DefaultCacheCapacity
+	}
+
+	return &LRUCache{
+		capacity: capacity,
+		ll:       list.New(),
+		items:    make(map[string]*list.Element),
+	}
+}
+
+func (c *LRUCache) Get(key string) (*CacheEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.items[key]
+	if !ok {
+		return nil, false
+	}
+
+	c.ll.MoveToFront(el)
+	return el.Value.(*lruItem).entry, true
+}
+
+func (c *LRUCache) Set(key string, entry *CacheEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.items[key]; ok {
+		el.Value.(*lruItem).entry = entry
+		c.ll.MoveToFront(el)
+		return
+	}
+
+	c.items[key] = c.ll.PushFront(&lruItem{key: key, entry: entry})
+	for c.ll.Len() > c.capacity {
+		oldest := c.ll.Back()
+		c.ll.Remove(oldest)
+		delete(c.items, oldest.Value.(*lruItem).key)
+	}
+}
+
+func (c *LRUCache) Delete(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.items[key]; ok {
+		c.ll.Remove(el)
+		delete(c.items, key)
+	}
+}
+
+// Len returns the number of the cached entries
+func (c *LRUCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.ll.Len()
+}
+
+// sendCacheable returns the fresh cached response of the GET request if there is one,
+// otherwise it sends the request, with the validators of the stale cached response if it has any,
+// and caches the cacheable response.
+func (c *BaseAPIClient) sendCacheable(req *http.Request) (*Response, error) {
+	key := c.requestKey(req)
+
+	var ttl time.Duration
+	if meta := RequestMetaFromContext(req.Context()); meta != nil {
+		ttl = meta.CacheTTL
+	}
+
+	entry, cached := c.ResponseCache.Get(key)
+	if cached && entry.Fresh(time.Now()) {
+		return entry.Response.fromCache(), nil
+	}
+
+	if cached {
+		req = revalidateRequest(req, entry.Response)
+	}
+
+	response, err := c.sendCoalesced(req)
+	if err != nil {
+		return response, err
+	}
+
+	now := time.Now()
+	if cached && response.StatusCode == http.StatusNotModified {
+		revalidated := *entry.Response.Response
+		revalidated.Header = entry.Response.Header.Clone()
+		for _, name := range []string{"Cache-Control", "Date", "Expires", "ETag", "Last-Modified", "Age"} {
+			if values := response.Header.Values(name); len(values) > 0 {
+				revalidated.Header[http.CanonicalHeaderKey(name)] = values
+			}
+		}
+
+		stored := &Response{Response: &revalidated, Body: entry.Response.Body, CompressedSize: entry.Response.CompressedSize}
+		if expires, ok := cacheExpiry(revalidated.Header, now, ttl); ok {
+			c.ResponseCache.Set(key, &CacheEntry{Response: stored, Expires: expires})
+		} else {
+			c.ResponseCache.Delete(key)
+		}
+
+		return stored.fromCache(), nil
+	}
+
+	if response.StatusCode != http.StatusOK || isAPIErrorResponse(req, response) {
+		return response, nil
+	}
+
+	if expires, ok := cacheExpiry(response.Header, now, ttl); ok {
+		c.ResponseCache.Set(key, &CacheEntry{Response: response, Expires: expires})
+	} else if cached {
+		c.ResponseCache.Delete(key)
+	}
+
+	return response, nil
+}
+
+// isAPIErrorResponse reports whether the successful response carries a failed error envelope of RequestMeta.ErrorType,
+// which the generated request rejects, so it must not be cached.
+func isAPIErrorResponse(req *http.Request, response *Response) bool {
+	meta := RequestMetaFromContext(req.Context())
+	if meta == nil || meta.ErrorType == nil {
+		return false
+	}
+
+	apiErr, ok := newAPIError(meta.ErrorType)
+	return ok && DecodeAPIError(req, response, apiErr) != nil
+}
+
+// fromCache returns a copy of the cached response with FromCache set
+func (r *Response) fromCache() *Response {
+	cached := *r
+	cached.FromCache = true
+	return &cached
+}
+
+// revalidateRequest returns a copy of the request with the conditional headers of the stale response
+func revalidateRequest(req *http.Request, stale *Response) *http.Request {
+	etag, lastModified := stale.Header.Get("ETag"), stale.Header.Get("Last-Modified")
+	if etag == "" && lastModified == "" {
+		return req
+	}
+
+	req = req.Clone(req.Context())
+	if etag != "" {
+		req.Header.Set("If-None-Match", etag)
+	}
+
+	if lastModified != "" {
+		req.Header.Set("If-Modified-Since", lastModified)
+	}
+
+	return req
+}
+
+// cacheExpiry returns when the response becomes stale, false is returned if the response should not be stored.
+// The forced ttl overrides the max-age and the no-cache directives, but the no-store directive is always honored.
+// The stale responses are stored only when they can be revalidated.
+func cacheExpiry(header http.Header, now time.Time, ttl time.Duration) (time.Time, bool) {
+	directives := parseCacheControl(header.Get("Cache-Control"))
+	if _, ok := directives["no-store"]; ok {
+		return time.Time{}, false
+	}
+
+	expires := now
+	if ttl > 0 {
+		expires = now.Add(ttl)
+	} else if _, ok := directives["no-cache"]; !ok {
+		if maxAge, err := strconv.ParseInt(directives["max-age"], 10, 64); err == nil {
+			age, _ := strconv.ParseInt(header.Get("Age"), 10, 64)
+			expires = now.Add(time.Duration(maxAge-age) * time.Second)
+		}
+	}
+
+	if expires.After(now) {
+		return expires, true
+	}
+
+	return expires, header.Get("ETag") != "" || header.Get("Last-Modified") != ""
+}
+
+func parseCacheControl(value string) map[string]string {
+	directives := make(map[string]string)
+	for _, directive := range strings.Split(value, ",") {
+		directive = strings.TrimSpace(directive)
+		if directive == "" {
+			continue
+		}
+
+		name, arg, _ := strings.Cut(directive, "=")
+		directives[strings.ToLower(strings.TrimSpace(name))] = strings.Trim(strings.TrimSpace(arg), `"`)
+	}
+
+	return directives
+}
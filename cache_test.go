@@ -0,0 +1,190 @@
+package requestgen
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLRUCache(t *testing.T) {
+	cache := NewLRUCache(2)
+	entry := func(body string) *CacheEntry {
+		return &CacheEntry{Response: &Response{Body: []byte(body)}}
+	}
+
+	cache.Set("a", entry("a"))
+	cache.Set("b", entry("b"))
+
+	// a becomes the most recently used, so b is evicted
+	_, ok := cache.Get("a")
+	assert.True(t, ok)
+	cache.Set("c", entry("c"))
+
+	_, ok = cache.Get("b")
+	assert.False(t, ok)
+	assert.Equal(t, 2, cache.Len())
+
+	cache.Delete("a")
+	_, ok = cache.Get("a")
+	assert.False(t, ok)
+	assert.Equal(t, 1, cache.Len())
+}
+
+func TestCacheExpiry(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name    string
+		header  http.Header
+		ttl     time.Duration
+		expires time.Time
+		store   bool
+	}{
+		{"max-age", http.Header{"Cache-Control": {"public, max-age=60"}}, 0, now.Add(time.Minute), true},
+		{"max-age with age", http.Header{"Cache-Control": {"max-age=60"}, "Age": {"20"}}, 0, now.Add(40 * time.Second), true},
+		{"forced ttl", http.Header{"Cache-Control": {"no-cache"}}, time.Hour, now.Add(time.Hour), true},
+		{"no-store", http.Header{"Cache-Control": {"no-store"}}, time.Hour, time.Time{}, false},
+		{"no-cache with etag", http.Header{"Cache-Control": {"no-cache, max-age=60"}, "Etag": {`"v1"`}}, 0, now, true},
+		{"no validators", http.Header{}, 0, now, false},
+		{"last-modified", http.Header{"Last-Modified": {"Mon, 02 Jan 2006 15:04:05 GMT"}}, 0, now, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expires, store := cacheExpiry(tt.header, now, tt.ttl)
+			assert.Equal(t, tt.store, store)
+			if tt.store {
+				assert.Equal(t, tt.expires, expires)
+			}
+		})
+	}
+}
+
+func TestBaseAPIClient_ResponseCache(t *testing.T) {
+	var calls int32
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.Header().Set("Cache-Control", "max-age=60")
+		w.Write([]byte(`["BTC","ETH"]`))
+	})
+	client.ResponseCache = NewLRUCache(0)
+
+	send := func() *Response {
+		req, err := client.NewRequest(context.Background(), "GET", "/v1/currencies", nil, nil)
+		assert.NoError(t, err)
+
+		response, err := client.SendRequest(req)
+		assert.NoError(t, err)
+		return response
+	}
+
+	response := send()
+	assert.False(t, response.FromCache)
+
+	response = send()
+	assert.True(t, response.FromCache)
+	assert.Equal(t, `["BTC","ETH"]`, string(response.Body))
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+
+	// POST requests are not cached
+	for i := 0; i < 2; i++ {
+		req, err := client.NewRequest(context.Background(), "POST", "/v1/currencies", nil, nil)
+		assert.NoError(t, err)
+
+		response, err := client.SendRequest(req)
+		assert.NoError(t, err)
+		assert.False(t, response.FromCache)
+	}
+	assert.Equal(t, int32(3), atomic.LoadInt32(&calls))
+}
+
+func TestBaseAPIClient_ResponseCache_Revalidate(t *testing.T) {
+	var calls, notModified int32
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.Header().Set("Cache-Control", "no-cache")
+		w.Header().Set("ETag", `"v1"`)
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			atomic.AddInt32(&notModified, 1)
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+
+		w.Write([]byte(`{"symbol":"BTC-USDT"}`))
+	})
+	client.ResponseCache = NewLRUCache(0)
+
+	for i := 0; i < 3; i++ {
+		req, err := client.NewRequest(context.Background(), "GET", "/v1/symbols", nil, nil)
+		assert.NoError(t, err)
+
+		response, err := client.SendRequest(req)
+		assert.NoError(t, err)
+		assert.Equal(t, i > 0, response.FromCache)
+		assert.Equal(t, http.StatusOK, response.StatusCode)
+		assert.Equal(t, `{"symbol":"BTC-USDT"}`, string(response.Body))
+	}
+
+	assert.Equal(t, int32(3), atomic.LoadInt32(&calls))
+	assert.Equal(t, int32(2), atomic.LoadInt32(&notModified))
+}
+
+func TestBaseAPIClient_ResponseCache_CacheTTL(t *testing.T) {
+	var calls int32
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.Write([]byte(`{}`))
+	})
+	client.ResponseCache = NewLRUCache(0)
+
+	send := func(ctx context.Context, authorization string) *Response {
+		req, err := client.NewRequest(ctx, "GET", "/v1/fees", nil, nil)
+		assert.NoError(t, err)
+		req.Header.Set("Authorization", authorization)
+
+		response, err := client.SendRequest(req)
+		assert.NoError(t, err)
+		return response
+	}
+
+	// the response has no freshness information, so it's not cached without the forced ttl
+	send(context.Background(), "a")
+	assert.False(t, send(context.Background(), "a").FromCache)
+
+	ctx := WithRequestMeta(context.Background(), &RequestMeta{CacheTTL: time.Minute})
+	send(ctx, "a")
+	assert.True(t, send(ctx, "a").FromCache)
+
+	// the responses of different identities are cached separately
+	assert.False(t, send(ctx, "b").FromCache)
+	assert.Equal(t, int32(4), atomic.LoadInt32(&calls))
+}
+
+func TestBaseAPIClient_ResponseCache_ErrorEnvelope(t *testing.T) {
+	var calls int32
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.Header().Set("Cache-Control", "max-age=60")
+		w.Write([]byte(`{"code":429000,"message":"too many requests"}`))
+	})
+	client.ResponseCache = NewLRUCache(0)
+
+	// the failed envelope of the successful response is not cached, even with the forced ttl
+	ctx := WithRequestMeta(context.Background(), &RequestMeta{CacheTTL: time.Minute, ErrorType: &testAPIError{}})
+	for i := 0; i < 3; i++ {
+		req, err := client.NewRequest(ctx, "GET", "/v1/ticker", nil, nil)
+		assert.NoError(t, err)
+
+		response, err := client.SendRequest(req)
+		if assert.NoError(t, err) {
+			assert.False(t, response.FromCache)
+		}
+	}
+
+	assert.Equal(t, int32(3), atomic.LoadInt32(&calls))
+	assert.Equal(t, 0, client.ResponseCache.(*LRUCache).Len())
+}
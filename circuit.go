@@ -0,0 +1,293 @@
+package requestgen
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// CircuitState is the state of a circuit
+type CircuitState int
+
+const (
+	// CircuitClosed lets the requests through
+	CircuitClosed CircuitState = iota
+
+	// CircuitOpen rejects the requests with ErrCircuitOpen until the cooldown ends
+	CircuitOpen
+
+	// CircuitHalfOpen lets a limited number of trial requests through, their results decide whether to close the circuit
+	CircuitHalfOpen
+)
+
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	}
+
+	return fmt.Sprintf("CircuitState(%d)", int(s))
+}
+
+const (
+	defaultCircuitCooldown         = 30 * time.Second
+	defaultCircuitHalfOpenRequests = 1
+)
+
+// ErrCircuitOpen is returned when the circuit of the endpoint is open, the request is not sent.
+type ErrCircuitOpen struct {
+	// Key is the circuit key, the method and the path template, e.g. "POST /v1/orders/:orderID"
+	Key string
+
+	// RetryAfter is the remaining cooldown of the circuit
+	RetryAfter time.Duration
+}
+
+func (e *ErrCircuitOpen) Error() string {
+	return fmt.Sprintf("circuit of %s is open, retry after %s", e.Key, e.RetryAfter)
+}
+
+// IsCircuitOpen reports whether the request was rejected by an open circuit
+func IsCircuitOpen(err error) bool {
+	var circuitErr *ErrCircuitOpen
+	return errors.As(err, &circuitErr)
+}
+
+// CircuitBreaker stops sending requests to a failing endpoint for a while.
+// The circuits are keyed by the method and the path template of the request, see RequestMeta.
+//
+// A closed circuit opens when the consecutive failures reach ConsecutiveFailures,
+// or the failure ratio in the current Window reaches FailureRatio.
+// An open circuit becomes half-open after Cooldown, then HalfOpenRequests trial requests are let through.
+// The circuit closes if all of them succeed, otherwise it opens again.
+type CircuitBreaker struct {
+	// ConsecutiveFailures is the number of consecutive failures that opens the circuit, zero disables this threshold.
+	ConsecutiveFailures int
+
+	// FailureRatio is the failure ratio in the Window that opens the circuit, zero disables this threshold.
+	FailureRatio float64
+
+	// Window is the fixed window of the failure ratio, the counts are reset when a window ends.
+	Window time.Duration
+
+	// MinRequests is the minimum number of requests in the window before the failure ratio is checked.
+	MinRequests int
+
+	// Cooldown is how long the circuit stays open, 30 seconds by default.
+	Cooldown time.Duration
+
+	// HalfOpenRequests is the number of the trial requests in the half-open state, one by default.
+	HalfOpenRequests int
+
+	// IsFailure decides whether the result of a request is a failure, IsRetryable is used if it's nil,
+	// so the server errors, 429 and the network errors count, but the other client errors don't.
+	IsFailure func(response *Response, err error) bool
+
+	// OnStateChange is called when the state of a circuit changes, it's called with the lock held,
+	// so it must not call the methods of the circuit breaker.
+	OnStateChange func(key string, from, to CircuitState)
+
+	mu       sync.Mutex
+	circuits map[string]*circuit
+}
+
+type circuit struct {
+	state CircuitState
+
+	consecutiveFailures int
+
+	windowStart        time.Time
+	requests, failures int
+
+	openedAt time.Time
+
+	halfOpenInFlight, halfOpenSuccesses int
+}
+
+// NewCircuitBreaker creates a CircuitBreaker that opens after the given consecutive failures for the cooldown
+func NewCircuitBreaker(consecutiveFailures int, cooldown time.Duration) *CircuitBreaker {
+	return &CircuitBreaker{
+		ConsecutiveFailures: consecutiveFailures,
+		Cooldown:            cooldown,
+	}
+}
+
+// State returns the current state of the circuit
+func (b *CircuitBreaker) State(key string) CircuitState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if c, ok := b.circuits[key]; ok {
+		return c.state
+	}
+
+	return CircuitClosed
+}
+
+// Allow checks whether a request can be sent to the endpoint, *ErrCircuitOpen is returned if not.
+// Each allowed request must be followed by a Record call.
+func (b *CircuitBreaker) Allow(key string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	c := b.circuit(key)
+	switch c.state {
+	case CircuitOpen:
+		remaining := b.cooldown() - time.Since(c.openedAt)
+		if remaining > 0 {
+			return &ErrCircuitOpen{Key: key, RetryAfter: remaining}
+		}
+
+		b.setState(key, c, CircuitHalfOpen)
+		fallthrough
+
+	case CircuitHalfOpen:
+		if c.halfOpenInFlight+c.halfOpenSuccesses >= b.halfOpenRequests() {
+			return &ErrCircuitOpen{Key: key}
+		}
+
+		c.halfOpenInFlight++
+	}
+
+	return nil
+}
+
+// Record records the result of an allowed request, ctx is the context of the request.
+// The result is neutral if the request is canceled or ctx is done, e.g. the caller's own deadline is exceeded,
+// it's counted as neither a success nor a failure of the endpoint.
+func (b *CircuitBreaker) Record(ctx context.Context, key string, response *Response, err error) {
+	if errors.Is(err, context.Canceled) || (err != nil && ctx.Err() != nil) {
+		b.release(key)
+		return
+	}
+
+	failed := err != nil
+	if b.IsFailure != nil {
+		failed = b.IsFailure(response, err)
+	} else if failed {
+		failed = IsRetryable(err)
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	c := b.circuit(key)
+	switch c.state {
+	case CircuitHalfOpen:
+		if c.halfOpenInFlight > 0 {
+			c.halfOpenInFlight--
+		}
+
+		if failed {
+			b.open(key, c)
+			return
+		}
+
+		c.halfOpenSuccesses++
+		if c.halfOpenSuccesses >= b.halfOpenRequests() {
+			b.setState(key, c, CircuitClosed)
+		}
+
+	case CircuitClosed:
+		now := time.Now()
+		if b.Window > 0 && now.Sub(c.windowStart) >= b.Window {
+			c.windowStart = now
+			c.requests, c.failures = 0, 0
+		}
+
+		c.requests++
+		if !failed {
+			c.consecutiveFailures = 0
+			return
+		}
+
+		c.failures++
+		c.consecutiveFailures++
+
+		if b.ConsecutiveFailures > 0 && c.consecutiveFailures >= b.ConsecutiveFailures {
+			b.open(key, c)
+			return
+		}
+
+		if b.FailureRatio > 0 && c.requests >= b.MinRequests &&
+			float64(c.failures)/float64(c.requests) >= b.FailureRatio {
+			b.open(key, c)
+		}
+	}
+}
+
+// release frees the half-open trial slot taken by Allow without recording a result
+func (b *CircuitBreaker) release(key string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	c := b.circuit(key)
+	if c.state == CircuitHalfOpen && c.halfOpenInFlight > 0 {
+		c.halfOpenInFlight--
+	}
+}
+
+func (b *CircuitBreaker) circuit(key string) *circuit {
+	if b.circuits == nil {
+		b.circuits = make(map[string]*circuit)
+	}
+
+	c, ok := b.circuits[key]
+	if !ok {
+		c = &circuit{windowStart: time.Now()}
+		b.circuits[key] = c
+	}
+
+	return c
+}
+
+func (b *CircuitBreaker) open(key string, c *circuit) {
+	c.openedAt = time.Now()
+	b.setState(key, c, CircuitOpen)
+}
+
+func (b *CircuitBreaker) setState(key string, c *circuit, state CircuitState) {
+	from := c.state
+	c.state = state
+
+	// reset the counts of the new state
+	c.consecutiveFailures = 0
+	c.windowStart = time.Now()
+	c.requests, c.failures = 0, 0
+	c.halfOpenInFlight, c.halfOpenSuccesses = 0, 0
+
+	if b.OnStateChange != nil && from != state {
+		b.OnStateChange(key, from, state)
+	}
+}
+
+func (b *CircuitBreaker) cooldown() time.Duration {
+	if b.Cooldown > 0 {
+		return b.Cooldown
+	}
+	return defaultCircuitCooldown
+}
+
+func (b *CircuitBreaker) halfOpenRequests() int {
+	if b.HalfOpenRequests > 0 {
+		return b.HalfOpenRequests
+	}
+	return defaultCircuitHalfOpenRequests
+}
+
+// circuitKey returns the circuit key of the request, the path template of the request meta is used if it's available
+func circuitKey(req *http.Request) string {
+	path := req.URL.Path
+	if meta := RequestMetaFromContext(req.Context()); meta != nil && meta.PathTemplate != "" {
+		path = meta.PathTemplate
+	}
+
+	return req.Method + " " + path
+}
@@ -0,0 +1,181 @@
+package requestgen
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var errServer = &ErrResponse{Response: &Response{Response: &http.Response{StatusCode: http.StatusInternalServerError}}}
+
+func TestCircuitBreaker_ConsecutiveFailures(t *testing.T) {
+	type change struct{ from, to CircuitState }
+	var changes []change
+
+	breaker := NewCircuitBreaker(2, 50*time.Millisecond)
+	breaker.OnStateChange = func(key string, from, to CircuitState) {
+		assert.Equal(t, "GET /v1/ticker", key)
+		changes = append(changes, change{from, to})
+	}
+
+	key := "GET /v1/ticker"
+	for i := 0; i < 2; i++ {
+		assert.NoError(t, breaker.Allow(key))
+		breaker.Record(context.Background(), key, nil, errServer)
+	}
+	assert.Equal(t, CircuitOpen, breaker.State(key))
+
+	err := breaker.Allow(key)
+	var circuitErr *ErrCircuitOpen
+	if assert.True(t, errors.As(err, &circuitErr)) {
+		assert.Equal(t, key, circuitErr.Key)
+		assert.True(t, circuitErr.RetryAfter > 0)
+	}
+
+	// other endpoints are not affected
+	assert.NoError(t, breaker.Allow("POST /v1/orders"))
+
+	// a failed trial request opens the circuit again
+	time.Sleep(60 * time.Millisecond)
+	assert.NoError(t, breaker.Allow(key))
+	assert.True(t, IsCircuitOpen(breaker.Allow(key)), "only one trial request is allowed")
+	breaker.Record(context.Background(), key, nil, errServer)
+	assert.Equal(t, CircuitOpen, breaker.State(key))
+
+	// a successful trial request closes the circuit
+	time.Sleep(60 * time.Millisecond)
+	assert.NoError(t, breaker.Allow(key))
+	breaker.Record(context.Background(), key, nil, nil)
+	assert.Equal(t, CircuitClosed, breaker.State(key))
+
+	assert.Equal(t, []change{
+		{CircuitClosed, CircuitOpen},
+		{CircuitOpen, CircuitHalfOpen},
+		{CircuitHalfOpen, CircuitOpen},
+		{CircuitOpen, CircuitHalfOpen},
+		{CircuitHalfOpen, CircuitClosed},
+	}, changes)
+}
+
+func TestCircuitBreaker_FailureRatio(t *testing.T) {
+	breaker := &CircuitBreaker{FailureRatio: 0.5, MinRequests: 4, Window: time.Minute}
+
+	key := "POST /v1/orders"
+	results := []error{errServer, nil, errServer, nil, errServer}
+	for i, err := range results {
+		assert.NoError(t, breaker.Allow(key))
+		breaker.Record(context.Background(), key, nil, err)
+
+		if i < 3 {
+			assert.Equal(t, CircuitClosed, breaker.State(key), "attempt %d", i)
+		}
+	}
+	assert.Equal(t, CircuitOpen, breaker.State(key))
+
+	// the client errors don't count as failures
+	breaker = &CircuitBreaker{ConsecutiveFailures: 1}
+	assert.NoError(t, breaker.Allow(key))
+	breaker.Record(context.Background(), key, nil, &ErrResponse{Response: &Response{Response: &http.Response{StatusCode: http.StatusBadRequest}}})
+	assert.Equal(t, CircuitClosed, breaker.State(key))
+}
+
+func TestBaseAPIClient_CircuitBreaker(t *testing.T) {
+	var attempts int32
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&attempts, 1)
+		w.WriteHeader(http.StatusBadGateway)
+	})
+	client.CircuitBreaker = NewCircuitBreaker(2, time.Minute)
+	client.RetryPolicy = &ExponentialBackoff{MaxAttempts: 5, InitialInterval: time.Millisecond}
+
+	ctx := WithRequestMeta(context.Background(), &RequestMeta{PathTemplate: "/api/v1/orders/:orderID"})
+	req, err := client.NewRequest(ctx, "GET", "/api/v1/orders/123", nil, nil)
+	assert.NoError(t, err)
+
+	// the retries stop when the circuit opens
+	_, err = client.SendRequest(req)
+	assert.True(t, IsCircuitOpen(err))
+	assert.False(t, IsRetryable(err))
+	assert.Equal(t, int32(2), atomic.LoadInt32(&attempts))
+	assert.Equal(t, CircuitOpen, client.CircuitBreaker.State("GET /api/v1/orders/:orderID"))
+}
+
+func TestCircuitBreaker_Canceled(t *testing.T) {
+	breaker := NewCircuitBreaker(1, 10*time.Millisecond)
+
+	key := "GET /v1/ticker"
+	assert.NoError(t, breaker.Allow(key))
+	breaker.Record(context.Background(), key, nil, errServer)
+	assert.Equal(t, CircuitOpen, breaker.State(key))
+
+	// a canceled trial request neither closes nor opens the circuit, and frees the trial slot
+	time.Sleep(20 * time.Millisecond)
+	assert.NoError(t, breaker.Allow(key))
+	breaker.Record(context.Background(), key, nil, context.Canceled)
+	assert.Equal(t, CircuitHalfOpen, breaker.State(key))
+
+	// the caller's own deadline is neutral too
+	deadlineCtx, cancel := context.WithDeadline(context.Background(), time.Now())
+	defer cancel()
+
+	assert.NoError(t, breaker.Allow(key))
+	breaker.Record(deadlineCtx, key, nil, &url.Error{Op: "Get", URL: "/v1/ticker", Err: context.DeadlineExceeded})
+	assert.Equal(t, CircuitHalfOpen, breaker.State(key))
+
+	assert.NoError(t, breaker.Allow(key))
+	breaker.Record(context.Background(), key, nil, nil)
+	assert.Equal(t, CircuitClosed, breaker.State(key))
+
+	// a closed circuit is not opened by the caller's deadline
+	assert.NoError(t, breaker.Allow(key))
+	breaker.Record(deadlineCtx, key, nil, context.DeadlineExceeded)
+	assert.Equal(t, CircuitClosed, breaker.State(key))
+}
+
+func TestBaseAPIClient_CircuitBreaker_CallerDeadline(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(100 * time.Millisecond):
+		}
+	})
+	client.CircuitBreaker = NewCircuitBreaker(1, time.Minute)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	req, err := client.NewRequest(ctx, "GET", "/v1/ticker", nil, nil)
+	assert.NoError(t, err)
+
+	_, err = client.SendRequest(req)
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+	assert.Equal(t, CircuitClosed, client.CircuitBreaker.State("GET /v1/ticker"))
+}
+
+func TestBaseAPIClient_CircuitBreaker_SendStreamRequest(t *testing.T) {
+	var attempts int32
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&attempts, 1)
+		w.WriteHeader(http.StatusBadGateway)
+	})
+	client.CircuitBreaker = NewCircuitBreaker(2, time.Minute)
+
+	req, err := client.NewRequest(context.Background(), "GET", "/v1/market/histories", nil, nil)
+	assert.NoError(t, err)
+
+	for i := 0; i < 2; i++ {
+		_, err = client.SendStreamRequest(req)
+		assert.False(t, IsCircuitOpen(err))
+	}
+	assert.Equal(t, CircuitOpen, client.CircuitBreaker.State("GET /v1/market/histories"))
+
+	_, err = client.SendStreamRequest(req)
+	assert.True(t, IsCircuitOpen(err))
+	assert.Equal(t, int32(2), atomic.LoadInt32(&attempts))
+}
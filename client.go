@@ -35,6 +35,9 @@ type AuthenticatedAPIClient interface {
 
 const defaultHTTPTimeout = time.Second * 30
 
+// FormContentType is the content type of the url.Values payload
+const FormContentType = "application/x-www-form-urlencoded"
+
 var defaultHttpClient = &http.Client{
 	Timeout: defaultHTTPTimeout,
 }
@@ -50,47 +53,248 @@ var defaultHttpClient = &http.Client{
 type BaseAPIClient struct {
 	BaseURL    *url.URL
 	HttpClient *http.Client
+
+	// RetryPolicy is used for retrying the failed requests, requests are sent only once if it's nil.
+	RetryPolicy RetryPolicy
+
+	// ErrorType is the error envelope type of the API, e.g. &APIError{}.
+	// When it's set, the error responses are decoded into a new value of this type, see DecodeAPIError.
+	ErrorType error
+
+	// MaxResponseBytes limits the size of the buffered response body, zero means no limit.
+	// It can be overridden per request by RequestMeta.MaxResponseBytes.
+	MaxResponseBytes int64
+
+	// Signer signs the requests built by NewAuthenticatedRequest, the retried requests are signed again
+	// so that they carry a fresh timestamp.
+	Signer Signer
+
+	// TokenSource provides the access token of the requests built by NewAuthenticatedRequest.
+	// When the API server responds 401 to a request with the token, the request is sent again once with a new token.
+	TokenSource TokenSource
+
+	// CircuitBreaker stops sending the requests to the failing endpoints for a while when it's set,
+	// the requests to an endpoint with an open circuit fail with *ErrCircuitOpen.
+	CircuitBreaker *CircuitBreaker
+
+	// CoalesceRequests makes the identical concurrent GET requests share one HTTP request and its response.
+	// The requests are identical if they have the same URL and identity, see CoalesceIdentity.
+	// It can be enabled per request with RequestMeta.Coalesce, e.g. by the -coalesce option of requestgen.
+	CoalesceRequests bool
+
+	// CoalesceIdentity returns the authenticated identity of the request for coalescing.
+	// When it's nil, the key header of the HMACSigner or the Authorization header is used.
+	CoalesceIdentity func(req *http.Request) string
+
+	// ResponseCache caches the responses of the GET requests when it's set, e.g. NewLRUCache(0).
+	// The Cache-Control max-age and no-store directives are honored, and the stale responses are revalidated
+	// with their ETag or Last-Modified header. RequestMeta.CacheTTL forces the freshness lifetime of a request.
+	ResponseCache ResponseCache
+
+	// RateLimiters holds the rate limiters of the client, the generated requests with the -clientRateLimiter option
+	// wait for the limiter in it instead of the package-level limiter, so that each client has its own budget.
+	RateLimiters *RateLimiterRegistry
+
+	// AdaptiveRateLimiter reads the rate limit budget from the headers of every response when it's set,
+	// the generated requests with the -adaptiveRateLimit option wait for it before sending the request.
+	AdaptiveRateLimiter *AdaptiveRateLimiter
+
+	// Observer receives the metrics of each HTTP request, see Observer.
+	Observer Observer
+
+	// EnableCompression asks the API server for gzip or deflate encoded responses
+	// by setting the Accept-Encoding header if the request does not have one.
+	EnableCompression bool
+
+	middlewares []Middleware
+
+	coalescing coalesceGroup
 }
 
 // NewRequest create new API request. Relative url can be provided in refURL.
+// The url.Values payload is sent as a form-urlencoded body, the *MultipartPayload payload is streamed as
+// a multipart/form-data body, []byte and string payloads are sent as is, and the other payloads are encoded in JSON.
 func (c *BaseAPIClient) NewRequest(
 	ctx context.Context, method, refPath string, params url.Values, payload interface{},
 ) (*http.Request, error) {
+	ref, err := url.Parse(refPath)
+	if err != nil {
+		return nil, err
+	}
+
+	pathURL := c.BaseURL.ResolveReference(ref)
+	if params != nil {
+		pathURL.RawQuery = params.Encode()
+	}
+
+	if multipartPayload, ok := payload.(*MultipartPayload); ok {
+		body := newMultipartBody(multipartPayload)
+		req, err := http.NewRequestWithContext(ctx, method, pathURL.String(), body)
+		if err != nil {
+			return nil, err
+		}
+
+		req.Header.Set("Content-Type", body.ContentType())
+		return req, nil
+	}
+
 	body, err := castPayload(payload)
 	if err != nil {
 		return nil, err
 	}
 
-	ref, err := url.Parse(refPath)
+	req, err := http.NewRequestWithContext(ctx, method, pathURL.String(), bytes.NewReader(body))
 	if err != nil {
 		return nil, err
 	}
 
-	pathURL := c.BaseURL.ResolveReference(ref)
-	if params != nil {
-		pathURL.RawQuery = params.Encode()
+	if _, ok := payload.(url.Values); ok {
+		req.Header.Set("Content-Type", FormContentType)
 	}
 
-	return http.NewRequestWithContext(ctx, method, pathURL.String(), bytes.NewReader(body))
+	return req, nil
 }
 
-// SendRequest sends the request to the API server and handle the response
+// SendRequest sends the request through the middlewares to the API server and handle the response.
+// The request is sent again according to the RetryPolicy if it's set.
+// The GET requests are answered from the ResponseCache when it's set and the cached response is fresh.
+// When the request is coalesced, the concurrent identical requests share the response of the first request,
+// the waiting requests return when their own context is done, and send the request again if the first one is canceled.
 func (c *BaseAPIClient) SendRequest(req *http.Request) (*Response, error) {
 	if c.HttpClient == nil {
 		c.HttpClient = defaultHttpClient
 	}
 
+	if c.ResponseCache != nil && req.Method == http.MethodGet {
+		return c.sendCacheable(req)
+	}
+
+	return c.sendCoalesced(req)
+}
+
+func (c *BaseAPIClient) sendCoalesced(req *http.Request) (*Response, error) {
+	if key, ok := c.coalesceKey(req); ok {
+		return c.coalescing.do(req.Context(), key, func() (*Response, error) {
+			return c.send(req)
+		})
+	}
+
+	return c.send(req)
+}
+
+func (c *BaseAPIClient) send(req *http.Request) (*Response, error) {
+	if len(c.middlewares) == 0 {
+		return c.sendRequest(req)
+	}
+
+	return chainMiddlewares(DoerFunc(c.sendRequest), c.middlewares).Do(req)
+}
+
+func (c *BaseAPIClient) sendRequest(req *http.Request) (*Response, error) {
+	response, err := c.sendRequestWithRetry(req)
+
+	if token := requestToken(req); token != nil && c.TokenSource != nil && StatusCode(err) == http.StatusUnauthorized {
+		if nextReq, ok := c.reauthenticate(req, token); ok {
+			return c.sendRequestWithRetry(nextReq)
+		}
+	}
+
+	return response, err
+}
+
+func (c *BaseAPIClient) sendRequestWithRetry(req *http.Request) (*Response, error) {
+	if c.RetryPolicy == nil {
+		return c.sendRequestOnce(req)
+	}
+
+	ctx := req.Context()
+	for attempt := 1; ; attempt++ {
+		response, err := c.sendRequestOnce(req)
+		if err == nil {
+			return response, nil
+		}
+
+		var delay time.Duration
+		var retry bool
+		if policy, ok := c.RetryPolicy.(RequestRetryPolicy); ok {
+			delay, retry = policy.BackoffRequest(req, attempt, response, err)
+		} else {
+			delay, retry = c.RetryPolicy.Backoff(attempt, response, err)
+		}
+
+		if !retry {
+			return response, err
+		}
+
+		if err := sleepContext(ctx, delay); err != nil {
+			return response, err
+		}
+
+		nextReq, ok := rewindRequest(req)
+		if !ok {
+			return response, err
+		}
+
+		// the signature of the previous attempt may have expired during the backoff
+		if c.Signer != nil && isSignedRequest(nextReq) {
+			if err := c.sign(nextReq); err != nil {
+				return response, err
+			}
+		}
+
+		req = nextReq
+	}
+}
+
+func (c *BaseAPIClient) sendRequestOnce(req *http.Request) (response *Response, err error) {
+	if c.CircuitBreaker != nil {
+		key := circuitKey(req)
+		if err := c.CircuitBreaker.Allow(key); err != nil {
+			return nil, err
+		}
+
+		defer func() {
+			c.CircuitBreaker.Record(req.Context(), key, response, err)
+		}()
+	}
+
+	c.negotiateCompression(req)
+
+	if c.Observer != nil {
+		start := time.Now()
+		defer func() {
+			statusCode, responseBytes := 0, int64(-1)
+			if response != nil {
+				statusCode, responseBytes = response.StatusCode, int64(len(response.Body))
+			} else if tooLarge, ok := err.(*ErrResponseTooLarge); ok {
+				statusCode = tooLarge.StatusCode
+			}
+
+			c.observe(req, statusCode, responseBytes, start, err)
+		}()
+	}
+
 	resp, err := c.HttpClient.Do(req)
 	if err != nil {
 		return nil, err
 	}
 
+	if c.AdaptiveRateLimiter != nil {
+		c.AdaptiveRateLimiter.Update(resp.Header)
+	}
+
 	// newResponse reads the response body and return a new Response object
-	response, err := NewResponse(resp)
+	response, err = NewResponseWithLimit(resp, c.maxResponseBytes(req))
 	if err != nil {
 		return response, err
 	}
 
+	if c.ErrorType != nil {
+		if apiErr, ok := newAPIError(c.ErrorType); ok {
+			return response, DecodeAPIError(req, response, apiErr)
+		}
+	}
+
 	// Check error, if there is an error, return the ErrorResponse struct type
 	if response.IsError() {
 		return response, &ErrResponse{Response: response, Body: response.Body, Request: req}
@@ -99,6 +303,20 @@ func (c *BaseAPIClient) SendRequest(req *http.Request) (*Response, error) {
 	return response, nil
 }
 
+func (c *BaseAPIClient) negotiateCompression(req *http.Request) {
+	if c.EnableCompression && req.Header.Get("Accept-Encoding") == "" {
+		req.Header.Set("Accept-Encoding", defaultAcceptEncoding)
+	}
+}
+
+func (c *BaseAPIClient) maxResponseBytes(req *http.Request) int64 {
+	if meta := RequestMetaFromContext(req.Context()); meta != nil && meta.MaxResponseBytes != 0 {
+		return meta.MaxResponseBytes
+	}
+
+	return c.MaxResponseBytes
+}
+
 func castPayload(payload interface{}) ([]byte, error) {
 	if payload != nil {
 		switch v := payload.(type) {
@@ -108,6 +326,9 @@ func castPayload(payload interface{}) ([]byte, error) {
 		case []byte:
 			return v, nil
 
+		case url.Values:
+			return []byte(v.Encode()), nil
+
 		default:
 			body, err := json.Marshal(v)
 			return body, err
@@ -122,8 +343,20 @@ type ErrResponse struct {
 
 	Request *http.Request
 	Body    []byte
+
+	// APIError is the decoded error envelope, it's set when the error type of the API is given.
+	APIError error
 }
 
 func (e *ErrResponse) Error() string {
+	if e.APIError != nil {
+		return fmt.Sprintf("request failed with status code: %d, api error: %v", e.Response.StatusCode, e.APIError)
+	}
+
 	return fmt.Sprintf("request failed with status code: %d, body: %q", e.Response.StatusCode, string(e.Body))
 }
+
+// Unwrap returns the decoded error envelope, so that errors.As can extract the API error
+func (e *ErrResponse) Unwrap() error {
+	return e.APIError
+}
@@ -61,6 +61,15 @@ type Field struct {
 
 	// IsSlice indicates whether the field is a slice type
 	IsSlice bool
+
+	// IsMultipart indicates the field is an io.Reader sent as a file part of the multipart/form-data body
+	IsMultipart bool
+
+	// FileName is the file name of the multipart file part
+	FileName string
+
+	// IsIdempotencyKey indicates the parameter value is the generated idempotency key, see GetIdempotencyKey
+	IsIdempotencyKey bool
 }
 
 func parseDefaultTag(tags *structtag.Tags, fieldName string, argKind types.BasicKind) (interface{}, error) {
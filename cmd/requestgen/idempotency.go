@@ -0,0 +1,109 @@
+package main
+
+import (
+	"fmt"
+	"go/types"
+	"net/textproto"
+	"strings"
+	"text/template"
+)
+
+const defaultIdempotencyKeyHeader = "Idempotency-Key"
+
+// IdempotencyKey is the request field tagged with idempotencyKey, e.g.
+//
+//	clientOrderID *string `param:"clientOid" idempotencyKey:"param"`
+//	idempotencyKey *string `idempotencyKey:"header=Idempotency-Key"`
+//
+// The key is generated once and kept in the field, so the retries of the same request object send the same key.
+type IdempotencyKey struct {
+	Field Field
+
+	// Header is the header name of the key, the key is sent as the parameter of the field if it's empty.
+	Header string
+}
+
+// parseIdempotencyKeyTag parses the idempotencyKey tag value: param, header or header=Name
+func parseIdempotencyKeyTag(value string) (header string, err error) {
+	location, name, hasName := strings.Cut(value, "=")
+	switch location {
+	case "param":
+		if hasName {
+			return "", fmt.Errorf("idempotencyKey:%q does not take a name, the parameter name is given by the param tag", value)
+		}
+
+		return "", nil
+
+	case "header":
+		if !hasName {
+			return defaultIdempotencyKeyHeader, nil
+		}
+
+		if name == "" || strings.ContainsAny(name, " \t:") {
+			return "", fmt.Errorf("idempotencyKey:%q has an invalid header name", value)
+		}
+
+		return textproto.CanonicalMIMEHeaderKey(name), nil
+	}
+
+	return "", fmt.Errorf("unknown idempotencyKey location %q, valid: param, header or header=Name", value)
+}
+
+// setIdempotencyKey registers the idempotency key field, a request can only have one idempotency key.
+func (g *Generator) setIdempotencyKey(key *IdempotencyKey) error {
+	if g.idempotencyKey != nil {
+		return fmt.Errorf("%s and %s are both tagged with idempotencyKey", g.idempotencyKey.Field.Name, key.Field.Name)
+	}
+
+	if !isTypeString(key.Field.ArgType) {
+		return fmt.Errorf("idempotency key field %s must be a string, got %s", key.Field.Name, key.Field.ArgType)
+	}
+
+	if key.Field.IsSlug || key.Field.IsMultipart {
+		return fmt.Errorf("idempotency key field %s must be a query or body parameter", key.Field.Name)
+	}
+
+	if key.Field.DefaultValuer != "" || key.Field.Default != nil {
+		return fmt.Errorf("idempotency key field %s must not have a default, the key is generated", key.Field.Name)
+	}
+
+	g.importPackage("github.com/google/uuid")
+	g.idempotencyKey = key
+	return nil
+}
+
+func (g *Generator) generateIdempotencyKeyMethod(funcMap template.FuncMap) error {
+	var tpl = template.Must(template.New("idempotencyKey").Funcs(funcMap).Parse(`
+{{- $recv := .ReceiverName }}
+{{- $field := print $recv "." .Field.Name }}
+
+// GetIdempotencyKey returns the idempotency key of the request. A new key is generated and kept in {{ .Field.Name }}
+// if it's empty, so the retries of the same request object send the same key. The key can be restored with {{ .Field.SetterName }}.
+func ({{ $recv }} *{{ typeString .StructType }}) GetIdempotencyKey() string {
+{{- if .Field.Optional }}
+	if {{ $field }} == nil || len(*{{ $field }}) == 0 {
+		key := uuid.New().String()
+		{{ $field }} = &key
+	}
+
+	return *{{ $field }}
+{{- else }}
+	if len({{ $field }}) == 0 {
+		{{ $field }} = uuid.New().String()
+	}
+
+	return {{ $field }}
+{{- end }}
+}
+`))
+
+	return tpl.Execute(&g.buf, struct {
+		StructType   types.Type
+		ReceiverName string
+		Field        Field
+	}{
+		StructType:   g.structType,
+		ReceiverName: g.receiverName,
+		Field:        g.idempotencyKey.Field,
+	})
+}
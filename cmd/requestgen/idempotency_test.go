@@ -0,0 +1,29 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_parseIdempotencyKeyTag(t *testing.T) {
+	header, err := parseIdempotencyKeyTag("param")
+	if assert.NoError(t, err) {
+		assert.Empty(t, header)
+	}
+
+	header, err = parseIdempotencyKeyTag("header")
+	if assert.NoError(t, err) {
+		assert.Equal(t, "Idempotency-Key", header)
+	}
+
+	header, err = parseIdempotencyKeyTag("header=x-client-request-id")
+	if assert.NoError(t, err) {
+		assert.Equal(t, "X-Client-Request-Id", header)
+	}
+
+	for _, value := range []string{"", "query", "param=clientOid", "header=", "header=X Key"} {
+		_, err := parseIdempotencyKeyTag(value)
+		assert.Error(t, err, value)
+	}
+}
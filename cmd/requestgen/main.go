@@ -50,19 +50,37 @@ var (
 	useDynamicPath = flag.Bool("dynamicPath", false, "enable dynamic API path")
 
 	parameterType       = flag.String("parameterType", "map", "the parameter type to build, valid: map or url, default: map")
+	bodyEncoding        = flag.String("bodyEncoding", "json", "the encoding of the request body, valid: json or form, default: json")
 	responseTypeSel     = flag.String("responseType", "interface{}", "the response type for decoding the API response, this type should be defined in the same package. if not given, interface{} will be used")
 	responseDataTypeSel = flag.String("responseDataType", "", "the data type in the response. this is used to decode data with the response wrapper")
 	responseDataField   = flag.String("responseDataField", "", "the field name of the inner data of the response type")
+	maxResponseBytes    = flag.Int64("maxResponseBytes", 0, "the size limit of the response body in bytes, overrides the limit of the API client, -1 means no limit")
+	responseDecoder     = flag.String("responseDecoder", "", "force the decoder of the response regardless of the content type, valid: json, xml, csv, text or a registered media type")
+	cacheTTL            = flag.Duration("cacheTTL", 0, "force the freshness lifetime of the cached response, e.g. 5m, only for GET requests")
+	coalesce            = flag.Bool("coalesce", false, "share one in-flight HTTP request among the identical concurrent calls, only for GET requests")
+	paginate            = flag.String("paginate", "", "generate the Iterate method, e.g. page:page,total:TotalPage or cursor:after,next:NextCursor[,items:Items]")
+	stream              = flag.Bool("stream", false, "generate the DoStream method, which returns the response without buffering the body")
+	errorTypeSel        = flag.String("errorType", "", "the error envelope type for decoding the error responses, the decoded error can be extracted with errors.As")
 
 	rateLimiter               = flag.String("rateLimiter", "", "MUST be 'L+N/M', L is the burst, N is the events count, M is the time duration(s,ms). e.q. 3+2/1s")
 	sharedRateLimiterTypeName = flag.String("sharedRateLimiterTypeName", "", "the name of shared rate limiter")
+	clientRateLimiter         = flag.Bool("clientRateLimiter", false, "look up the rate limiter in the RateLimiterRegistry of the API client, the package-level limiter is the template and the fallback")
+	weight                    = flag.Int("weight", 0, "the number of the rate limiter tokens taken by the request, the GetRequestWeight() int method of the request type overrides it")
+	adaptiveRateLimit         = flag.Bool("adaptiveRateLimit", false, "wait for the adaptive rate limiter of the API client, which is driven by the rate limit headers of the responses")
 
 	outputStdout = flag.Bool("stdout", false, "output generated content to the stdout")
 	output       = flag.String("output", "", "output file name; default srcdir/<type>_string.go")
 
 	rateLimiterRegex = regexp.MustCompile(`^(\d+)\+(\d+)\/(\d+(?:ms|s))$`)
+
+	declaredSharedRateLimiters sharedRateLimiters
 )
 
+func init() {
+	flag.Var(&declaredSharedRateLimiters, "declareSharedRateLimiter",
+		"declare a shared rate limiter 'Name=L+N/M' for -sharedRateLimiterTypeName Name, can be repeated. -type is optional with this option")
+}
+
 var outputSuffix = "_requestgen.go"
 
 // File holds a single parsed file and associated data.
@@ -100,6 +118,9 @@ type Generator struct {
 
 	responseType, responseDataType types.Type
 
+	// errorType is the error envelope type of the API
+	errorType types.Type
+
 	// apiClientField if the request defined the client field with APIClient,
 	// it means we can generate the Do() method
 	apiClientField         *string
@@ -116,6 +137,9 @@ type Generator struct {
 
 	slugs []Field
 
+	// multipartFields are the file parts of the multipart/form-data body
+	multipartFields []Field
+
 	simpleTypes          map[string]string
 	simpleTypeValueNames map[string][]Literal
 	stringTypeValues     map[string][]string
@@ -125,6 +149,15 @@ type Generator struct {
 		Rate  rate.Limit
 		Burst int64
 	}
+
+	// dynamicWeight is true if the request type defines the GetRequestWeight method
+	dynamicWeight bool
+
+	// pagination is the parsed -paginate option
+	pagination *Pagination
+
+	// idempotencyKey is the field tagged with idempotencyKey
+	idempotencyKey *IdempotencyKey
 }
 
 func (g *Generator) importPackage(pkg string) {
@@ -210,6 +243,26 @@ func (g *Generator) checkClientInterface(field *ast.Field) {
 	}
 }
 
+// requestWeightMethod is the method of the request type that returns the dynamic rate limiter weight
+const requestWeightMethod = "GetRequestWeight"
+
+// hasRequestWeightMethod checks if the request type defines the GetRequestWeight() int method
+func hasRequestWeightMethod(t types.Type) (bool, error) {
+	obj, _, _ := types.LookupFieldOrMethod(t, true, nil, requestWeightMethod)
+	method, ok := obj.(*types.Func)
+	if !ok {
+		return false, nil
+	}
+
+	signature := method.Type().(*types.Signature)
+	if signature.Params().Len() != 0 || signature.Results().Len() != 1 ||
+		!types.Identical(signature.Results().At(0).Type(), types.Typ[types.Int]) {
+		return false, fmt.Errorf("%s.%s must be func() int, got %s", t, requestWeightMethod, signature)
+	}
+
+	return true, nil
+}
+
 func (g *Generator) parseStructFields(file *ast.File, typeSpec *ast.TypeSpec, structType *ast.StructType) {
 	typeDef := g.pkg.pkg.TypesInfo.Defs[typeSpec.Name]
 	fullTypeName := typeDef.Type().String()
@@ -225,6 +278,12 @@ func (g *Generator) parseStructFields(file *ast.File, typeSpec *ast.TypeSpec, st
 	}
 	g.receiverName = receiverName
 
+	dynamicWeight, err := hasRequestWeightMethod(g.structType)
+	if err != nil {
+		log.Fatal(err)
+	}
+	g.dynamicWeight = dynamicWeight
+
 	// iterate the field list (by syntax)
 	for _, field := range structType.Fields.List {
 		// each struct field AST could have multiple names in one line
@@ -267,7 +326,26 @@ func (g *Generator) parseStructFields(file *ast.File, typeSpec *ast.TypeSpec, st
 			continue
 		}
 
+		var isIdempotencyKey bool
+		var idempotencyKeyHeader string
+		if idempotencyKeyTag, _ := tags.Get("idempotencyKey"); idempotencyKeyTag != nil {
+			isIdempotencyKey = true
+			idempotencyKeyHeader, err = parseIdempotencyKeyTag(idempotencyKeyTag.Value())
+			if err != nil {
+				log.Fatalf("field %s: %v", name, err)
+			}
+		}
+
 		paramTag, err := tags.Get("param")
+		if isIdempotencyKey && idempotencyKeyHeader != "" {
+			if paramTag != nil {
+				log.Fatalf("field %s: the idempotency key in the header must not have a param tag", name)
+			}
+
+			// the header key is parsed like a body parameter, but it's not added to the parameters
+			paramTag, err = &structtag.Tag{Key: "param", Name: name}, nil
+		}
+
 		if err != nil {
 			log.Errorf("unable to get tag param: %v", err)
 			continue
@@ -320,6 +398,22 @@ func (g *Generator) parseStructFields(file *ast.File, typeSpec *ast.TypeSpec, st
 		isSecondsTime := paramTag.HasOption("seconds")
 		isQuery := paramTag.HasOption("query")
 		isSlug := paramTag.HasOption("slug")
+		isMultipart := paramTag.HasOption("multipart")
+
+		var fileName string
+		if isMultipart {
+			// the file field is sent as is, e.g. io.Reader or *os.File
+			optional = false
+			isPointer = false
+			argType = typeValue.Type
+			fileName = jsonKey
+
+			for _, option := range paramTag.Options {
+				if strings.HasPrefix(option, "filename=") {
+					fileName = strings.TrimPrefix(option, "filename=")
+				}
+			}
+		}
 
 		if isTime {
 			g.importPackage("time")
@@ -410,12 +504,27 @@ func (g *Generator) parseStructFields(file *ast.File, typeSpec *ast.TypeSpec, st
 			DefaultValuer:      defaultValuer,
 			File:               file,
 			IsSlice:            isSlice,
+			IsMultipart:        isMultipart,
+			FileName:           fileName,
 		}
 
 		log.Debugf("found field: %s type: %v", f.Name, f.Type)
 
+		if isIdempotencyKey {
+			f.IsIdempotencyKey = true
+			if err := g.setIdempotencyKey(&IdempotencyKey{Field: f, Header: idempotencyKeyHeader}); err != nil {
+				log.Fatal(err)
+			}
+
+			if idempotencyKeyHeader != "" {
+				continue
+			}
+		}
+
 		// query parameters
-		if isSlug {
+		if isMultipart {
+			g.multipartFields = append(g.multipartFields, f)
+		} else if isSlug {
 			g.slugs = append(g.slugs, f)
 		} else if isQuery {
 			g.queryFields = append(g.queryFields, f)
@@ -605,6 +714,7 @@ func (g *Generator) generate(typeName string) {
 	g.importPackage("regexp")
 	g.importPackage("reflect")
 	g.importPackage("sync")
+	g.importPackage("github.com/c9s/requestgen")
 
 	if g.apiClientField != nil && (*apiUrlStr != "" || *useDynamicPath) {
 		g.importPackage("net/url")
@@ -619,6 +729,23 @@ func (g *Generator) generate(typeName string) {
 		g.importPackage("golang.org/x/time/rate")
 	}
 
+	if g.rateLimiter.Rate != 0 || *sharedRateLimiterTypeName != "" {
+		// time is used for measuring the rate limiter wait
+		g.importPackage("time")
+	}
+
+	if g.pagination != nil {
+		if g.apiClientField == nil || (*apiUrlStr == "" && !*useDynamicPath) {
+			log.Fatalf("-paginate requires the API client field and the API url of %s", typeName)
+		}
+
+		if err := g.resolvePagination(g.pagination); err != nil {
+			log.Fatal(err)
+		}
+
+		g.importPackage("iter")
+	}
+
 	var usedPkgNames []string
 	for n := range g.importPackages {
 		usedPkgNames = append(usedPkgNames, n)
@@ -699,9 +826,18 @@ func (g *Generator) generate(typeName string) {
 		types.TypeString(field.ArgType, qf)
 	}
 
+	log.Debugf("registering imports from multipart fields: %v", g.multipartFields)
+	for _, field := range g.multipartFields {
+		types.TypeString(field.ArgType, qf)
+	}
+
 	types.TypeString(g.responseType, qf)
 	types.TypeString(g.responseDataType, qf)
 
+	if g.errorType != nil {
+		types.TypeString(g.errorType, qf)
+	}
+
 	var funcMap = templateFuncs(qf)
 	if len(g.usedImports) > 0 {
 		g.printf("import (")
@@ -726,6 +862,12 @@ func (g *Generator) generate(typeName string) {
 		log.Fatal(err)
 	}
 
+	if g.idempotencyKey != nil {
+		if err := g.generateIdempotencyKeyMethod(funcMap); err != nil {
+			log.Fatal(err)
+		}
+	}
+
 	log.Debugf("apiClientField: %v apiUrl: %v", g.apiClientField, apiUrlStr)
 	if g.apiClientField != nil && (*apiUrlStr != "" || *useDynamicPath) {
 		if err := g.generateDoMethod(funcMap); err != nil {
@@ -761,39 +903,99 @@ func (g *Generator) generateDoMethod(funcMap template.FuncMap) error {
 		template.New("do").Funcs(funcMap).Parse(`
 {{ $recv := .ReceiverName }}
 
-// GetPath returns the request path of the API
-func ({{- .ReceiverName }} * {{- typeString .StructType -}}) GetPath() string {
-	return "{{ .ApiUrl }}"
-}
-
-// Do generates the request object and send the request object to the API endpoint
-func ({{- .ReceiverName }} * {{- typeString .StructType -}}) Do(ctx context.Context) (
-{{- if and .ResponseDataType .ResponseDataField -}}
-	{{ typeString (toPointer .ResponseDataType) }}
-{{- else -}}
-	{{ typeString (toPointer .ResponseType) }}
-{{- end -}}
-	,error) {
+{{- define "wait-rate-limiter" }}
+	{{- $limiter := "" }}
 	{{- if ne .Rate 0.0 }}
-	if err := {{ typeString .StructType }}Limiter.Wait(ctx); err != nil {
+	{{-   $limiter = print (typeString .StructType) "Limiter" }}
+	{{- else if .SharedRateLimiterTypeName }}
+	{{-   $limiter = print .SharedRateLimiterTypeName "Limiter" }}
+	{{- end }}
+	{{- if $limiter }}
+	{{- if .ClientRateLimiter }}
+
+	rateLimiter := requestgen.ClientRateLimiter({{ .ReceiverName }}.{{ .ApiClientField }}, "
+	{{- if ne .Rate 0.0 }}{{ typeString .StructType }}{{ else }}{{ .SharedRateLimiterTypeName }}{{ end }}", {{ $limiter }})
+	{{-   $limiter = "rateLimiter" }}
+	{{- end }}
+
+	waitStart := time.Now()
+	{{- if or .DynamicWeight (gt .Weight 1) }}
+	if err := {{ $limiter }}.WaitN(ctx, meta.Weight); err != nil {
+	{{- else }}
+	if err := {{ $limiter }}.Wait(ctx); err != nil {
+	{{- end }}
+		requestgen.ObserveRateLimitWait({{ .ReceiverName }}.{{ .ApiClientField }}, meta, time.Since(waitStart), err)
 		return nil, err
 	}
-	{{- else if .SharedRateLimiterTypeName }}
-	if err := {{ .SharedRateLimiterTypeName }}Limiter.Wait(ctx); err != nil {
+	requestgen.ObserveRateLimitWait({{ .ReceiverName }}.{{ .ApiClientField }}, meta, time.Since(waitStart), nil)
+	{{- end }}
+	{{- if .AdaptiveRateLimit }}
+
+	if err := requestgen.WaitAdaptiveRateLimit(ctx, {{ .ReceiverName }}.{{ .ApiClientField }}, meta); err != nil {
 		return nil, err
 	}
 	{{- end }}
+	{{- if or $limiter .AdaptiveRateLimit }}
+	span.AddEvent("rate_limit_wait", requestgen.Attr(requestgen.AttrRateLimitWait, meta.RateLimitWait))
+	{{- end }}
+{{- end }}
 
+{{- define "request-meta" }}
+	meta := &requestgen.RequestMeta{
+		Name:         "{{ typeString .StructType }}",
+		Method:       "{{ .ApiMethod }}",
+		PathTemplate: {{ .ReceiverName }}.GetPath(),
+		{{- if .MaxResponseBytes }}
+		MaxResponseBytes: {{ .MaxResponseBytes }},
+		{{- end }}
+		{{- if .Coalesce }}
+		Coalesce: true,
+		{{- end }}
+		{{- if .CacheTTL }}
+		CacheTTL: {{ .CacheTTL.Nanoseconds }}, // {{ .CacheTTL }}
+		{{- end }}
+		{{- if .ErrorType }}
+		ErrorType: &{{ typeString .ErrorType }}{},
+		{{- end }}
+		{{- if .IdempotencyKey }}
+		IdempotencyKey: {{ .ReceiverName }}.GetIdempotencyKey(),
+		{{- end }}
+		{{- if .DynamicWeight }}
+		Weight: {{ .ReceiverName }}.GetRequestWeight(),
+		{{- else if gt .Weight 1 }}
+		Weight: {{ .Weight }},
+		{{- end }}
+	}
+	ctx = requestgen.WithRequestMeta(ctx, meta)
+
+	ctx, span := requestgen.StartRequestSpan(ctx, meta)
+	defer func() {
+		requestgen.EndSpan(span, err)
+	}()
+{{- end }}
+
+{{- define "new-request" }}
+	{{- $recv := .ReceiverName }}
     {{ $requestMethod := "NewRequest" }}
     {{- if .ApiAuthenticated -}}
     {{-    $requestMethod = "NewAuthenticatedRequest" }}
     {{- end -}}
 
-{{- if not .HasParameters }}
+{{- if .HasMultipartFields }}
+	params, err := {{ $recv }}.GetMultipartPayload()
+	if err != nil {
+		return nil, err
+	}
+{{- else if not .HasParameters }}
     // no body params
 	var params interface{}
 {{- else if and .HasParameters (ne .ApiMethod "GET") }}
+	{{- if eq .BodyEncoding "form" }}
+	// url.Values params are sent as a form-urlencoded body
+	params, err := {{ $recv }}.GetParametersQuery()
+	{{- else }}
 	params, err := {{ $recv }}.GetParameters()
+	{{- end }}
 	if err != nil {
 		return nil, err
 	}
@@ -852,11 +1054,43 @@ func ({{- .ReceiverName }} * {{- typeString .StructType -}}) Do(ctx context.Cont
 		return nil, err
 	}
 
+	{{- if and .IdempotencyKey .IdempotencyKey.Header }}
+
+	req.Header.Set("{{ .IdempotencyKey.Header }}", meta.IdempotencyKey)
+	{{- end }}
+
+	span.AddEvent("build_request")
+{{- end }}
+
+{{- define "do-response" }}
+	{{- $recv := .ReceiverName }}
+	{{- template "request-meta" . }}
+
+	{{- template "wait-rate-limiter" . }}
+
+	{{- template "new-request" . }}
+
 	response, err := {{ $recv }}.{{ .ApiClientField }}.SendRequest(req)
+	if response != nil && response.Response != nil {
+		span.SetAttributes(requestgen.Attr(requestgen.AttrStatusCode, response.StatusCode))
+	}
+
+	span.AddEvent("send_request")
 	if err != nil {
+		{{- if .ErrorType }}
+		return nil, requestgen.WrapAPIError(err, &{{ typeString .ErrorType }}{})
+		{{- else }}
 		return nil, err
+		{{- end }}
 	}
 
+	{{- if .ErrorType }}
+
+	if err := requestgen.DecodeAPIError(req, response, &{{ typeString .ErrorType }}{}); err != nil {
+		return nil, err
+	}
+	{{- end }}
+
 	var apiResponse {{ typeString .ResponseType }}
 
 	type responseUnmarshaler interface {
@@ -868,14 +1102,26 @@ func ({{- .ReceiverName }} * {{- typeString .StructType -}}) Do(ctx context.Cont
 			return nil, err
 		}	
 	} else {
-		// The line below checks the content type, however, some API server might not send the correct content type header,
-		// Hence, this is commented for backward compatibility
-		// response.IsJSON()
-		if err := response.DecodeJSON(&apiResponse); err != nil {
+	{{- if .ResponseDecoder }}
+		decoder, ok := requestgen.LookupDecoder("{{ .ResponseDecoder }}")
+		if !ok {
+			return nil, fmt.Errorf("response decoder of %s is not registered", "{{ .ResponseDecoder }}")
+		}
+
+		if err := response.DecodeWith(decoder, &apiResponse); err != nil {
 			return nil, err
 		}
+	{{- else }}
+		// The decoder is chosen by the content type of the response, JSON is used if the content type is unknown,
+		// since some API servers might not send the correct content type header.
+		if err := response.Decode(&apiResponse); err != nil {
+			return nil, err
+		}
+	{{- end }}
 	}
 
+	span.AddEvent("decode_response")
+
 	type responseValidator interface {
 		Validate() error
 	}
@@ -885,6 +1131,117 @@ func ({{- .ReceiverName }} * {{- typeString .StructType -}}) Do(ctx context.Cont
 			return nil, err
 		}	
 	}
+{{- end }}
+
+{{- define "iterate" }}
+{{- $recv := .ReceiverName }}
+{{- $p := .Pagination }}
+{{- $item := typeString $p.ItemType }}
+
+// Iterate sends the request page by page and yields the items until the last page or the first error.
+// The {{ $p.Param }} field of the request is advanced by the iteration.
+func ({{- .ReceiverName }} * {{- typeString .StructType -}}) Iterate(ctx context.Context) iter.Seq2[{{ $item }}, error] {
+	return func(yield func({{ $item }}, error) bool) {
+		{{- if eq $p.Mode "page" }}
+		{{- $pageType := typeString $p.ParamField.ArgType }}
+		page := {{ if eq $pageType "int" }}1{{ else }}{{ $pageType }}(1){{ end }}
+		{{- if $p.ParamField.IsPointer }}
+		if {{ $recv }}.{{ $p.Param }} != nil {
+			page = *{{ $recv }}.{{ $p.Param }}
+		}
+		{{- else }}
+		if {{ $recv }}.{{ $p.Param }} != 0 {
+			page = {{ $recv }}.{{ $p.Param }}
+		}
+		{{- end }}
+		{{- else }}
+		var cursor {{ typeString $p.ParamField.ArgType }}
+		{{- if $p.ParamField.IsPointer }}
+		if {{ $recv }}.{{ $p.Param }} != nil {
+			cursor = *{{ $recv }}.{{ $p.Param }}
+		}
+		{{- else }}
+		cursor = {{ $recv }}.{{ $p.Param }}
+		{{- end }}
+		{{- end }}
+
+		for {
+			{{- if eq $p.Mode "page" }}
+			{{ $recv }}.{{ $p.ParamField.SetterName }}(page)
+			{{ end }}
+			apiResponse, err := {{ $recv }}.doResponse(ctx)
+			if err != nil {
+				var zero {{ $item }}
+				yield(zero, err)
+				return
+			}
+			{{- if $p.DecodeData }}
+
+			var data {{ typeString .ResponseDataType }}
+			if err := json.Unmarshal(apiResponse.{{ .ResponseDataField }}, &data); err != nil {
+				var zero {{ $item }}
+				yield(zero, err)
+				return
+			}
+			{{- end }}
+
+			items := {{ $p.ItemsExpr }}
+			for _, item := range items {
+				if !yield(item, nil) {
+					return
+				}
+			}
+
+			{{- if eq $p.Mode "page" }}
+
+			if len(items) == 0 || int64(page) >= int64({{ $p.StopExpr }}) {
+				return
+			}
+
+			page++
+			{{- else }}
+
+			{{- if $p.ConvertStop }}
+			next := {{ typeString $p.ParamField.ArgType }}({{ $p.StopExpr }})
+			{{- else }}
+			next := {{ $p.StopExpr }}
+			{{- end }}
+			if len(items) == 0 || next == "" || next == cursor {
+				return
+			}
+
+			cursor = next
+			{{ $recv }}.{{ $p.ParamField.SetterName }}(cursor)
+			{{- end }}
+		}
+	}
+}
+{{- end }}
+
+// GetPath returns the request path of the API
+func ({{- .ReceiverName }} * {{- typeString .StructType -}}) GetPath() string {
+	return "{{ .ApiUrl }}"
+}
+
+// Do generates the request object and send the request object to the API endpoint
+func ({{- .ReceiverName }} * {{- typeString .StructType -}}) Do(ctx context.Context) (_ {{ if and .ResponseDataType .ResponseDataField -}}
+	{{ typeString (toPointer .ResponseDataType) }}
+{{- else -}}
+	{{ typeString (toPointer .ResponseType) }}
+{{- end -}}
+	, err error) {
+{{- if .Pagination }}
+	{{- if and .ResponseDataType .ResponseDataField }}
+	apiResponse, err := {{ $recv }}.doResponse(ctx)
+	if err != nil {
+		return nil, err
+	}
+	{{- else }}
+	return {{ $recv }}.doResponse(ctx)
+	{{- end }}
+{{- else }}
+	{{- template "do-response" . }}
+{{- end }}
 
 {{- if and .ResponseDataType .ResponseDataField }}
 	var data {{ typeString .ResponseDataType }}
@@ -892,10 +1249,56 @@ func ({{- .ReceiverName }} * {{- typeString .StructType -}}) Do(ctx context.Cont
 		return nil, err
 	}
 	return {{ referenceByType .ResponseDataType -}} data, nil
-{{- else }}
+{{- else if not .Pagination }}
 	return {{ referenceByType .ResponseType -}} apiResponse, nil
 {{- end }}
 }
+
+{{- if .Pagination }}
+
+// doResponse sends the request and returns the decoded response
+func ({{- .ReceiverName }} * {{- typeString .StructType -}}) doResponse(ctx context.Context) (_ {{ typeString (toPointer .ResponseType) }}, err error) {
+	{{- template "do-response" . }}
+
+	return {{ referenceByType .ResponseType -}} apiResponse, nil
+}
+
+{{- template "iterate" . }}
+{{- end }}
+
+{{- if .Stream }}
+
+// DoStream sends the request object to the API endpoint and returns the response without buffering the body,
+// the caller must close the returned response.
+func ({{- .ReceiverName }} * {{- typeString .StructType -}}) DoStream(ctx context.Context) (_ *requestgen.StreamingResponse, err error) {
+	{{- template "request-meta" . }}
+
+	{{- template "wait-rate-limiter" . }}
+
+	{{- template "new-request" . }}
+
+	streamingClient, ok := {{ $recv }}.{{ .ApiClientField }}.(requestgen.StreamingAPIClient)
+	if !ok {
+		return nil, fmt.Errorf("%T does not implement requestgen.StreamingAPIClient", {{ $recv }}.{{ .ApiClientField }})
+	}
+
+	response, err := streamingClient.SendStreamRequest(req)
+	if response != nil {
+		span.SetAttributes(requestgen.Attr(requestgen.AttrStatusCode, response.StatusCode))
+	}
+
+	span.AddEvent("send_request")
+	if err != nil {
+		{{- if .ErrorType }}
+		return nil, requestgen.WrapAPIError(err, &{{ typeString .ErrorType }}{})
+		{{- else }}
+		return nil, err
+		{{- end }}
+	}
+
+	return response, nil
+}
+{{- end }}
 `))
 	err := doFuncTemplate.Execute(&g.buf, struct {
 		StructType                     types.Type
@@ -907,11 +1310,25 @@ func ({{- .ReceiverName }} * {{- typeString .StructType -}}) Do(ctx context.Cont
 		ApiAuthenticated               bool
 		ResponseType, ResponseDataType types.Type
 		ResponseDataField              string
+		ErrorType                      types.Type
+		Stream                         bool
+		BodyEncoding                   string
+		ResponseDecoder                string
+		MaxResponseBytes               int64
+		Coalesce                       bool
+		CacheTTL                       time.Duration
 		HasSlugs                       bool
 		HasParameters                  bool
 		HasQueryParameters             bool
+		HasMultipartFields             bool
 		Rate                           rate.Limit
 		SharedRateLimiterTypeName      string
+		AdaptiveRateLimit              bool
+		ClientRateLimiter              bool
+		Pagination                     *Pagination
+		Weight                         int
+		DynamicWeight                  bool
+		IdempotencyKey                 *IdempotencyKey
 	}{
 		StructType:                g.structType,
 		ReceiverName:              g.receiverName,
@@ -923,11 +1340,25 @@ func ({{- .ReceiverName }} * {{- typeString .StructType -}}) Do(ctx context.Cont
 		ResponseType:              g.responseType,
 		ResponseDataType:          g.responseDataType,
 		ResponseDataField:         *responseDataField,
+		ErrorType:                 g.errorType,
+		Stream:                    *stream,
+		BodyEncoding:              *bodyEncoding,
+		ResponseDecoder:           responseDecoderMediaType(*responseDecoder),
+		MaxResponseBytes:          *maxResponseBytes,
+		Coalesce:                  *coalesce,
+		CacheTTL:                  *cacheTTL,
 		HasSlugs:                  len(g.slugs) > 0,
 		HasParameters:             len(g.fields) > 0,
 		HasQueryParameters:        len(g.queryFields) > 0,
+		HasMultipartFields:        len(g.multipartFields) > 0,
 		Rate:                      g.rateLimiter.Rate,
 		SharedRateLimiterTypeName: *sharedRateLimiterTypeName,
+		AdaptiveRateLimit:         *adaptiveRateLimit,
+		ClientRateLimiter:         *clientRateLimiter,
+		Pagination:                g.pagination,
+		Weight:                    *weight,
+		DynamicWeight:             g.dynamicWeight,
+		IdempotencyKey:            g.idempotencyKey,
 	})
 
 	return err
@@ -961,7 +1392,7 @@ if len({{ .Name }}) == 0 {
 	{{ .Name }} = {{ .ReceiverName }}.GetDefault{{ title .Name }}()
 
 	{{- else if .Required }}
-	return nil, fmt.Errorf("{{ .JsonKey }} is required, empty string given")
+	return nil, requestgen.NewValidationError("{{ .JsonKey }}", "{{ .JsonKey }} is required, empty string given")
 	{{- end }}
 }
 {{- else if .IsInt }}
@@ -974,7 +1405,7 @@ if {{ .Name }} == 0 {
 	{{ .Name }} = {{ .ReceiverName }}.GetDefault{{ title .Name }}()
 
 	{{- else if .Required }}
-	return nil, fmt.Errorf("{{ .JsonKey }} is required, 0 given")
+	return nil, requestgen.NewValidationError("{{ .JsonKey }}", "{{ .JsonKey }} is required, 0 given")
 	{{- end }}
 }
 
@@ -989,7 +1420,7 @@ if {{ .Name }}.IsZero() {
 	{{ .Name }} = {{ .ReceiverName }}.GetDefault{{ title .Name }}()
 
 	{{- else if .Required }}
-	return nil, fmt.Errorf("{{ .JsonKey }} is required, 0 given")
+	return nil, requestgen.NewValidationError("{{ .JsonKey }}", "{{ .JsonKey }} is required, 0 given")
 
 	{{- end }}
 }
@@ -1006,7 +1437,7 @@ if {{ .Name }}.IsZero() {
 			params[ "{{- .JsonKey -}}" ] = {{ .Name }}
 
 		default:
-			return nil, fmt.Errorf("{{ .JsonKey }} value %v is invalid", {{ .Name }})
+			return nil, requestgen.NewValidationError("{{ .JsonKey }}", "{{ .JsonKey }} value %v is invalid", {{ .Name }})
 
 	}
 	// END TEMPLATE check-valid-values
@@ -1067,7 +1498,10 @@ func ({{- $recv }} * {{- typeString .StructType -}} ) GetQueryParameters() (url.
 
 {{- range .QueryFields }}
 	// check {{ .Name }} field -> json key {{ .JsonKey }}
-{{- if .Optional }}
+{{- if .IsIdempotencyKey }}
+	// the idempotency key is generated once and kept in the request
+	params[ "{{- .JsonKey -}}" ] = {{ $recv }}.GetIdempotencyKey()
+{{- else if .Optional }}
 	if {{ $recv }}.{{ .Name }} != nil {
 		{{ .Name }} := *{{- $recv }}.{{ .Name }}
 
@@ -1123,7 +1557,10 @@ func ({{- $recv }} * {{- typeString .StructType -}} ) GetParameters() (map[strin
 {{- range .Fields }}
 	// check {{ .Name }} field -> json key {{ .JsonKey }}
 
-{{- if .Optional }}
+{{- if .IsIdempotencyKey }}
+	// the idempotency key is generated once and kept in the request
+	params[ "{{- .JsonKey -}}" ] = {{ $recv }}.GetIdempotencyKey()
+{{- else if .Optional }}
 	if {{ $recv }}.{{ .Name }} != nil {
 		{{ .Name }} := *{{- $recv }}.{{ .Name }}
 
@@ -1191,6 +1628,36 @@ func ({{- $recv }} *{{ typeString .StructType -}} ) GetParametersJSON() ([]byte,
 	return json.Marshal(params)
 }
 
+{{- if .MultipartFields }}
+
+// GetMultipartPayload builds the multipart/form-data payload with the parameters and the file fields
+func ({{- $recv }} * {{- typeString .StructType -}} ) GetMultipartPayload() (*requestgen.MultipartPayload, error) {
+	fields, err := {{ $recv }}.GetParametersQuery()
+	if err != nil {
+		return nil, err
+	}
+
+	payload := &requestgen.MultipartPayload{Fields: fields}
+
+{{- range .MultipartFields }}
+
+	if {{ $recv }}.{{ .Name }} != nil {
+		payload.Files = append(payload.Files, requestgen.MultipartFile{
+			FieldName: "{{ .JsonKey }}",
+			FileName:  "{{ .FileName }}",
+			Reader:    {{ $recv }}.{{ .Name }},
+		})
+	}
+	{{- if .Required }} else {
+		return nil, requestgen.NewValidationError("{{ .JsonKey }}", "{{ .JsonKey }} is required, nil given")
+	}
+	{{- end }}
+{{- end }}
+
+	return payload, nil
+}
+{{- end }}
+
 // GetSlugParameters builds and checks the slug parameters and return the result in a map object
 func ({{- $recv }} * {{- typeString .StructType -}} ) GetSlugParameters() (map[string]interface{}, error) {
 	var params = map[string]interface{}{}
@@ -1286,14 +1753,16 @@ func ({{- $recv }} * {{- typeString .StructType -}} ) GetSlugsMap() (map[string]
 		StructType                 types.Type
 		ReceiverName               string
 		QueryFields, Fields, Slugs []Field
+		MultipartFields            []Field
 		Qualifier                  types.Qualifier
 	}{
-		StructType:   g.structType,
-		ReceiverName: g.receiverName,
-		Fields:       g.fields,
-		QueryFields:  g.queryFields,
-		Slugs:        g.slugs,
-		Qualifier:    qf,
+		StructType:      g.structType,
+		ReceiverName:    g.receiverName,
+		Fields:          g.fields,
+		QueryFields:     g.queryFields,
+		Slugs:           g.slugs,
+		MultipartFields: g.multipartFields,
+		Qualifier:       qf,
 	})
 	if err != nil {
 		return err
@@ -1354,6 +1823,18 @@ func ({{- .ReceiverName }} * {{- typeString .StructType -}} ) {{ .Field.AdderNam
 		}
 	}
 
+	for _, field := range g.multipartFields {
+		err := setterFuncTemplate.Execute(&g.buf, accessorTemplateArgs{
+			Field:        field,
+			Qualifier:    qf,
+			StructType:   g.structType,
+			ReceiverName: g.receiverName,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
 	for _, field := range g.slugs {
 		err := setterFuncTemplate.Execute(&g.buf, accessorTemplateArgs{
 			Field:        field,
@@ -1366,12 +1847,25 @@ func ({{- .ReceiverName }} * {{- typeString .StructType -}} ) {{ .Field.AdderNam
 		}
 	}
 
+	// the idempotency key in the header is not a parameter, but it can be restored by the setter
+	if g.idempotencyKey != nil && g.idempotencyKey.Header != "" {
+		err := setterFuncTemplate.Execute(&g.buf, accessorTemplateArgs{
+			Field:        g.idempotencyKey.Field,
+			Qualifier:    qf,
+			StructType:   g.structType,
+			ReceiverName: g.receiverName,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
 func main() {
 	flag.Parse()
-	if len(*typeNamesStr) == 0 {
+	if len(*typeNamesStr) == 0 && len(declaredSharedRateLimiters) == 0 {
 		flag.Usage()
 		os.Exit(2)
 	}
@@ -1382,7 +1876,10 @@ func main() {
 
 	log.Debugf("args: %q", os.Args)
 
-	typeNames := strings.Split(*typeNamesStr, ",")
+	var typeNames []string
+	if len(*typeNamesStr) > 0 {
+		typeNames = strings.Split(*typeNamesStr, ",")
+	}
 	var tags []string
 	if len(*buildTags) > 0 {
 		tags = strings.Split(*buildTags, ",")
@@ -1416,37 +1913,55 @@ func main() {
 		intTypeValues:           make(map[string][]int64),
 	}
 
+	switch *bodyEncoding {
+	case "json", "form":
+	default:
+		log.Fatalf("unsupported body encoding %s, valid: json or form", *bodyEncoding)
+	}
+
+	if *coalesce && strings.ToUpper(*apiMethodStr) != "GET" {
+		log.Fatalf("-coalesce only applies to GET requests, got method %s", *apiMethodStr)
+	}
+
+	if *cacheTTL < 0 {
+		log.Fatalf("-cacheTTL must not be negative, got %s", *cacheTTL)
+	}
+
+	if *cacheTTL > 0 && strings.ToUpper(*apiMethodStr) != "GET" {
+		log.Fatalf("-cacheTTL only applies to GET requests, got method %s", *apiMethodStr)
+	}
+
+	if *paginate != "" {
+		pagination, err := parsePagination(*paginate)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		g.pagination = pagination
+	}
+
+	if *weight < 0 {
+		log.Fatalf("-weight must not be negative, got %d", *weight)
+	}
+
 	hasRateLimiter := rateLimiter != nil && *rateLimiter != ""
 	if sharedRateLimiterTypeName != nil && *sharedRateLimiterTypeName != "" && hasRateLimiter {
 		log.Fatal("Please choose between sharedRateLimiterTypeName or rateLimiterPerSecond")
 	}
-	if hasRateLimiter {
-		ok := rateLimiterRegex.MatchString(*rateLimiter)
-		if !ok {
-			log.Fatalf("%s is nuexpected format of rateLimiter flag, MUST be L+N/M", *rateLimiter)
-		}
+	if *clientRateLimiter && !hasRateLimiter && *sharedRateLimiterTypeName == "" {
+		log.Fatal("-clientRateLimiter requires -rateLimiter or -sharedRateLimiterTypeName")
+	}
 
+	if hasRateLimiter {
 		var err error
-		rateLimiterSlice := rateLimiterRegex.FindStringSubmatch(*rateLimiter)
-		if len(rateLimiterSlice) != 4 {
-			log.Fatalf("%s is an unexpected format of rateLimiter flag, MUST be L+N/M", *rateLimiter)
-		}
-
-		g.rateLimiter.Burst, err = strconv.ParseInt(rateLimiterSlice[1], 10, 64)
+		g.rateLimiter.Rate, g.rateLimiter.Burst, err = parseRateLimiter(*rateLimiter)
 		if err != nil {
-			log.Fatalf("failed to parse burst %s to int: %s", rateLimiterSlice[1], err)
+			log.Fatal(err)
 		}
 
-		n, err := strconv.ParseInt(rateLimiterSlice[2], 10, 64)
-		if err != nil {
-			log.Fatalf("failed to parse time interval %s to duration: %s", rateLimiterSlice[2], err)
-		}
-		d, err := time.ParseDuration(rateLimiterSlice[3])
-		if err != nil {
-			log.Fatalf("failed to parse events count %s to int: %s", rateLimiterSlice[3], err)
+		if int64(*weight) > g.rateLimiter.Burst {
+			log.Fatalf("-weight %d exceeds the burst %d of the rate limiter, the requests would never be sent", *weight, g.rateLimiter.Burst)
 		}
-
-		g.rateLimiter.Rate = rate.Every(d / time.Duration(n))
 	}
 
 	pkgs, err := loadPackages(args, tags)
@@ -1461,6 +1976,42 @@ func main() {
 	g.currentPackage = pkgs[0]
 	g.addPackage(pkgs[0])
 
+	if len(declaredSharedRateLimiters) > 0 {
+		outputName := declaredSharedRateLimiters.outputName(dir)
+		if len(typeNames) == 0 && *output != "" {
+			outputName = *output
+		}
+
+		src, err := generateSharedRateLimiters(pkgs[0], declaredSharedRateLimiters, outputName)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		if *outputStdout {
+			_, err = fmt.Fprint(os.Stdout, string(src))
+		} else {
+			err = ioutil.WriteFile(outputName, src, 0644)
+		}
+
+		if err != nil {
+			log.Fatalf("writing output: %s", err)
+		}
+
+		if len(typeNames) == 0 {
+			return
+		}
+	}
+
+	if *sharedRateLimiterTypeName != "" {
+		if err := checkSharedRateLimiter(pkgs[0], declaredSharedRateLimiters, *sharedRateLimiterTypeName); err != nil {
+			log.Fatal(err)
+		}
+
+		if l := declaredSharedRateLimiters.lookup(*sharedRateLimiterTypeName); l != nil && int64(*weight) > l.Burst {
+			log.Fatalf("-weight %d exceeds the burst %d of the rate limiter, the requests would never be sent", *weight, l.Burst)
+		}
+	}
+
 	// parse response type
 	if responseTypeSel != nil && *responseTypeSel != "" {
 		if *responseTypeSel == "interface{}" {
@@ -1506,6 +2057,19 @@ func main() {
 		}
 	}
 
+	// parse error type
+	if errorTypeSel != nil && *errorTypeSel != "" {
+		o, _, err := parseTypeSelector(*errorTypeSel, pkgs)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		g.errorType = o.Type()
+		if g.currentPackage.PkgPath != o.Pkg().Path() {
+			g.importPackage(o.Pkg().Path())
+		}
+	}
+
 	g.printf("// Code generated by \"requestgen %s\"; DO NOT EDIT.\n", strings.Join(os.Args[1:], " "))
 	g.newline()
 	g.newline()
@@ -1518,7 +2082,7 @@ func main() {
 	}
 
 	// Format the output.
-	src := formatBuffer(g.buf)
+	src := dropUnusedImports(formatBuffer(g.buf), "github.com/c9s/requestgen")
 
 	if *outputStdout {
 		_, err = fmt.Fprint(os.Stdout, string(src))
@@ -1661,6 +2225,22 @@ func loadPackages(patterns []string, tags []string) ([]*packages.Package, error)
 	return pkgs, nil
 }
 
+// responseDecoderMediaType resolves the short names of the built-in response decoders to the media types
+func responseDecoderMediaType(name string) string {
+	switch strings.ToLower(name) {
+	case "json":
+		return requestgen.MediaTypeJSON
+	case "xml":
+		return requestgen.MediaTypeXML
+	case "csv":
+		return requestgen.MediaTypeCSV
+	case "text":
+		return requestgen.MediaTypeText
+	default:
+		return name
+	}
+}
+
 // parseTimeFormat returns the Go time format constant for a given string.
 func parseTimeFormat(format string) string {
 	// only support known formats
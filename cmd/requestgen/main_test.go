@@ -1,6 +1,9 @@
 package main
 
 import (
+	"go/format"
+	"go/token"
+	"go/types"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -18,3 +21,32 @@ func Test_rateLimiterRegex(t *testing.T) {
 	assert.Equal(t, "100", slice[2])
 	assert.Equal(t, "500ms", slice[3])
 }
+
+func Test_generateDoMethod_defaultResponseType(t *testing.T) {
+	// no -responseType given, the default interface{} is used
+	assert.Equal(t, "interface{}", *responseTypeSel)
+
+	pkg := types.NewPackage("github.com/c9s/requestgen/example/api", "api")
+	apiClientField := "client"
+	g := Generator{
+		structType:     types.NewNamed(types.NewTypeName(token.NoPos, pkg, "NoParamRequest", nil), types.NewStruct(nil, nil), nil),
+		receiverName:   "n",
+		apiClientField: &apiClientField,
+		responseType:   types.NewInterfaceType(nil, nil),
+	}
+
+	qf := func(other *types.Package) string {
+		if other == pkg {
+			return ""
+		}
+		return other.Name()
+	}
+
+	g.printf("package api\n")
+	if assert.NoError(t, g.generateDoMethod(templateFuncs(qf))) {
+		src, err := format.Source(g.buf.Bytes())
+		if assert.NoError(t, err) {
+			assert.Contains(t, string(src), "Do(ctx context.Context) (_ interface{}, err error)")
+		}
+	}
+}
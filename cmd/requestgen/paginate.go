@@ -0,0 +1,178 @@
+package main
+
+import (
+	"fmt"
+	"go/types"
+	"strings"
+)
+
+const (
+	paginationPage   = "page"
+	paginationCursor = "cursor"
+)
+
+// Pagination is the parsed -paginate option, e.g. page:page,total:TotalPage or cursor:after,next:NextCursor.
+//
+// The page or cursor key is the request field that is advanced by the iteration,
+// the total or next key is the field of the response that tells where the iteration stops,
+// and the optional items key is the field of the response that holds the items.
+// The response fields are looked up in the response data type first, then the response type.
+type Pagination struct {
+	Mode string
+
+	// Param is the request field name of the page number or the cursor
+	Param string
+
+	// Stop is the response field name of the total page count or the next cursor
+	Stop string
+
+	// Items is the response field name of the items, the response data is used if it's empty.
+	Items string
+
+	// the resolved fields of the request and the response
+	ParamField *Field
+
+	// StopExpr and ItemsExpr are the expressions of the stop field and the items in the generated Iterate method
+	StopExpr, ItemsExpr string
+
+	ItemType types.Type
+
+	// ConvertStop is true if the next cursor of the response needs a conversion to the type of the cursor field
+	ConvertStop bool
+
+	// DecodeData is true if the response data is used by the expressions
+	DecodeData bool
+}
+
+func parsePagination(value string) (*Pagination, error) {
+	p := &Pagination{}
+	var stopKey string
+	for _, option := range strings.Split(value, ",") {
+		key, name, ok := strings.Cut(strings.TrimSpace(option), ":")
+		if !ok || name == "" {
+			return nil, fmt.Errorf("%q is an unexpected pagination option, MUST be key:field", option)
+		}
+
+		switch key {
+		case paginationPage, paginationCursor:
+			if p.Mode != "" {
+				return nil, fmt.Errorf("pagination %s conflicts with %s", key, p.Mode)
+			}
+
+			p.Mode = key
+			p.Param = name
+
+		case "total", "next":
+			stopKey = key
+			p.Stop = name
+
+		case "items":
+			p.Items = name
+
+		default:
+			return nil, fmt.Errorf("unknown pagination option %q, valid: page, cursor, total, next or items", key)
+		}
+	}
+
+	switch {
+	case p.Mode == "":
+		return nil, fmt.Errorf("pagination %q requires page:field or cursor:field", value)
+
+	case p.Mode == paginationPage && stopKey != "total":
+		return nil, fmt.Errorf("page pagination %q requires total:Field", value)
+
+	case p.Mode == paginationCursor && stopKey != "next":
+		return nil, fmt.Errorf("cursor pagination %q requires next:Field", value)
+	}
+
+	return p, nil
+}
+
+// resolvePagination resolves the request field and the response fields of the pagination
+func (g *Generator) resolvePagination(p *Pagination) error {
+	for _, fields := range [][]Field{g.queryFields, g.fields} {
+		for i := range fields {
+			if fields[i].Name == p.Param {
+				p.ParamField = &fields[i]
+			}
+		}
+	}
+
+	if p.ParamField == nil {
+		return fmt.Errorf("pagination field %s is not a parameter of %s", p.Param, g.structType)
+	}
+
+	if p.Mode == paginationPage && !isTypeInt(p.ParamField.ArgType) {
+		return fmt.Errorf("page field %s must be an integer, got %s", p.Param, p.ParamField.ArgType)
+	}
+
+	if p.Mode == paginationCursor && !isTypeString(p.ParamField.ArgType) {
+		return fmt.Errorf("cursor field %s must be a string, got %s", p.Param, p.ParamField.ArgType)
+	}
+
+	hasData := g.responseDataType != nil && *responseDataField != ""
+
+	stopExpr, stopType, ok := g.lookupResponseField(p.Stop, hasData)
+	if !ok {
+		return fmt.Errorf("pagination field %s is not found in the response", p.Stop)
+	}
+
+	if p.Mode == paginationPage && !isTypeInt(stopType) {
+		return fmt.Errorf("total field %s must be an integer, got %s", p.Stop, stopType)
+	}
+
+	if p.Mode == paginationCursor && !isTypeString(stopType) {
+		return fmt.Errorf("next field %s must be a string, got %s", p.Stop, stopType)
+	}
+
+	var itemsExpr string
+	var itemsType types.Type
+	if p.Items != "" {
+		itemsExpr, itemsType, ok = g.lookupResponseField(p.Items, hasData)
+		if !ok {
+			return fmt.Errorf("pagination items field %s is not found in the response", p.Items)
+		}
+	} else if hasData {
+		itemsExpr, itemsType = "data", g.responseDataType
+	} else {
+		return fmt.Errorf("pagination requires items:Field when the response data type is not given")
+	}
+
+	slice, ok := itemsType.Underlying().(*types.Slice)
+	if !ok {
+		return fmt.Errorf("pagination items %s must be a slice, got %s", itemsExpr, itemsType)
+	}
+
+	p.ConvertStop = p.Mode == paginationCursor && !types.Identical(stopType, p.ParamField.ArgType)
+	p.StopExpr = stopExpr
+	p.ItemsExpr = itemsExpr
+	p.ItemType = slice.Elem()
+	p.DecodeData = strings.HasPrefix(stopExpr, "data") || strings.HasPrefix(itemsExpr, "data")
+	return nil
+}
+
+// lookupResponseField returns the expression and the type of the response field,
+// it's looked up in the response data type first if the response has data, then in the response type.
+func (g *Generator) lookupResponseField(name string, hasData bool) (string, types.Type, bool) {
+	if hasData {
+		if t, ok := g.lookupField(g.responseDataType, name); ok {
+			return "data." + name, t, true
+		}
+	}
+
+	if t, ok := g.lookupField(g.responseType, name); ok {
+		return "apiResponse." + name, t, true
+	}
+
+	return "", nil, false
+}
+
+func (g *Generator) lookupField(t types.Type, name string) (types.Type, bool) {
+	obj, _, _ := types.LookupFieldOrMethod(t, true, g.currentPackage.Types, name)
+	field, ok := obj.(*types.Var)
+	if !ok || !field.IsField() {
+		return nil, false
+	}
+
+	return field.Type(), true
+}
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_parsePagination(t *testing.T) {
+	p, err := parsePagination("page:currentPage,total:TotalPage")
+	if assert.NoError(t, err) {
+		assert.Equal(t, &Pagination{Mode: paginationPage, Param: "currentPage", Stop: "TotalPage"}, p)
+	}
+
+	p, err = parsePagination("cursor:after, next:NextCursor, items:Items")
+	if assert.NoError(t, err) {
+		assert.Equal(t, &Pagination{Mode: paginationCursor, Param: "after", Stop: "NextCursor", Items: "Items"}, p)
+	}
+
+	for _, value := range []string{
+		"total:TotalPage",
+		"page:page",
+		"page:page,next:NextCursor",
+		"cursor:after,total:TotalPage",
+		"page:page,cursor:after,total:TotalPage",
+		"page:page,total:TotalPage,size:PageSize",
+		"page",
+	} {
+		_, err := parsePagination(value)
+		assert.Error(t, err, value)
+	}
+}
@@ -0,0 +1,168 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/fatih/camelcase"
+	"golang.org/x/time/rate"
+	"golang.org/x/tools/go/packages"
+)
+
+// parseRateLimiter parses the rate limiter spec 'L+N/M', L is the burst, N is the events count, M is the time duration
+func parseRateLimiter(value string) (rate.Limit, int64, error) {
+	slice := rateLimiterRegex.FindStringSubmatch(value)
+	if len(slice) != 4 {
+		return 0, 0, fmt.Errorf("%s is an unexpected format of rate limiter, MUST be L+N/M", value)
+	}
+
+	burst, err := strconv.ParseInt(slice[1], 10, 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to parse burst %s to int: %w", slice[1], err)
+	}
+
+	n, err := strconv.ParseInt(slice[2], 10, 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to parse events count %s to int: %w", slice[2], err)
+	}
+
+	if n == 0 {
+		return 0, 0, fmt.Errorf("the events count of rate limiter %s must not be zero", value)
+	}
+
+	d, err := time.ParseDuration(slice[3])
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to parse time interval %s to duration: %w", slice[3], err)
+	}
+
+	return rate.Every(d / time.Duration(n)), burst, nil
+}
+
+// sharedRateLimiter is a shared rate limiter declared by -declareSharedRateLimiter
+type sharedRateLimiter struct {
+	Name  string
+	Rate  rate.Limit
+	Burst int64
+}
+
+// VarName returns the variable name of the limiter, which is referenced by -sharedRateLimiterTypeName Name
+func (l sharedRateLimiter) VarName() string {
+	return l.Name + "Limiter"
+}
+
+// sharedRateLimiters is the repeatable -declareSharedRateLimiter flag
+type sharedRateLimiters []sharedRateLimiter
+
+func (s *sharedRateLimiters) String() string {
+	var specs []string
+	for _, l := range *s {
+		specs = append(specs, fmt.Sprintf("%s=%v/%d", l.Name, l.Rate, l.Burst))
+	}
+	return strings.Join(specs, ",")
+}
+
+// Set parses the declaration 'Name=L+N/M'
+func (s *sharedRateLimiters) Set(value string) error {
+	name, spec, ok := strings.Cut(value, "=")
+	if !ok {
+		return fmt.Errorf("%s is an unexpected format of shared rate limiter, MUST be Name=L+N/M", value)
+	}
+
+	if !token.IsIdentifier(name) {
+		return fmt.Errorf("shared rate limiter name %q is not a valid identifier", name)
+	}
+
+	if s.lookup(name) != nil {
+		return fmt.Errorf("shared rate limiter %s is declared twice", name)
+	}
+
+	limit, burst, err := parseRateLimiter(spec)
+	if err != nil {
+		return err
+	}
+
+	*s = append(*s, sharedRateLimiter{Name: name, Rate: limit, Burst: burst})
+	return nil
+}
+
+func (s *sharedRateLimiters) lookup(name string) *sharedRateLimiter {
+	for i := range *s {
+		if (*s)[i].Name == name {
+			return &(*s)[i]
+		}
+	}
+	return nil
+}
+
+// outputName returns the file name of the declarations, which is named after the first limiter
+func (s *sharedRateLimiters) outputName(dir string) string {
+	fn := strings.Join(camelcase.Split((*s)[0].Name), "_")
+	return filepath.Join(dir, strings.ToLower(fn+"_limiter"+outputSuffix))
+}
+
+var sharedRateLimitersTemplate = template.Must(template.New("sharedRateLimiters").Parse(`
+import "golang.org/x/time/rate"
+{{ range . }}
+// {{ .VarName }} is shared by the requests generated with -sharedRateLimiterTypeName {{ .Name }}
+var {{ .VarName }} = rate.NewLimiter({{ .Rate }}, {{ .Burst }})
+{{ end }}`))
+
+// generateSharedRateLimiters generates the declarations of the shared rate limiters.
+// A limiter can only be declared once in a package, so the variable must not be declared outside the output file.
+func generateSharedRateLimiters(pkg *packages.Package, limiters sharedRateLimiters, outputName string) ([]byte, error) {
+	for _, l := range limiters {
+		obj := pkg.Types.Scope().Lookup(l.VarName())
+		if obj == nil {
+			continue
+		}
+
+		declaredIn := pkg.Fset.Position(obj.Pos()).Filename
+		if filepath.Base(declaredIn) != filepath.Base(outputName) {
+			return nil, fmt.Errorf("%s is already declared in %s", l.VarName(), declaredIn)
+		}
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "// Code generated by \"requestgen %s\"; DO NOT EDIT.\n\n", strings.Join(os.Args[1:], " "))
+	fmt.Fprintf(&buf, "package %s\n", pkg.Name)
+	if err := sharedRateLimitersTemplate.Execute(&buf, limiters); err != nil {
+		return nil, err
+	}
+
+	return formatBuffer(buf), nil
+}
+
+// checkSharedRateLimiter checks that the shared rate limiter referenced by -sharedRateLimiterTypeName is declared,
+// either by -declareSharedRateLimiter of this run, or in the package.
+func checkSharedRateLimiter(pkg *packages.Package, declared sharedRateLimiters, name string) error {
+	if declared.lookup(name) != nil {
+		return nil
+	}
+
+	varName := sharedRateLimiter{Name: name}.VarName()
+	obj := pkg.Types.Scope().Lookup(varName)
+	if obj == nil {
+		return fmt.Errorf("shared rate limiter %s is not declared in package %s, declare it with -declareSharedRateLimiter %s=L+N/M",
+			varName, pkg.Name, name)
+	}
+
+	if _, ok := obj.(*types.Var); !ok {
+		return fmt.Errorf("shared rate limiter %s must be a variable, got %s", varName, obj)
+	}
+
+	for _, method := range []string{"Wait", "WaitN"} {
+		if m, _, _ := types.LookupFieldOrMethod(obj.Type(), true, nil, method); m == nil {
+			return fmt.Errorf("shared rate limiter %s of type %s has no %s method", varName, obj.Type(), method)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/time/rate"
+)
+
+func Test_parseRateLimiter(t *testing.T) {
+	limit, burst, err := parseRateLimiter("5+10/2s")
+	assert.NoError(t, err)
+	assert.Equal(t, rate.Limit(5), limit)
+	assert.Equal(t, int64(5), burst)
+
+	_, _, err = parseRateLimiter("5+10")
+	assert.Error(t, err)
+
+	_, _, err = parseRateLimiter("5+0/1s")
+	assert.Error(t, err)
+}
+
+func Test_sharedRateLimiters_Set(t *testing.T) {
+	var limiters sharedRateLimiters
+	assert.NoError(t, limiters.Set("Public=20+10/1s"))
+	assert.NoError(t, limiters.Set("PrivateOrder=5+100/500ms"))
+
+	if assert.Len(t, limiters, 2) {
+		assert.Equal(t, "PublicLimiter", limiters[0].VarName())
+		assert.Equal(t, rate.Limit(10), limiters[0].Rate)
+		assert.Equal(t, int64(20), limiters[0].Burst)
+		assert.Equal(t, rate.Limit(200), limiters[1].Rate)
+	}
+
+	assert.Equal(t, "public_limiter_requestgen.go", limiters.outputName(""))
+	assert.NotNil(t, limiters.lookup("PrivateOrder"))
+	assert.Nil(t, limiters.lookup("Private"))
+
+	assert.Error(t, limiters.Set("Public=1+1/1s"), "declared twice")
+	assert.Error(t, limiters.Set("Public"), "no spec")
+	assert.Error(t, limiters.Set("1Public=1+1/1s"), "invalid identifier")
+	assert.Error(t, limiters.Set("Other=1+1/1h"), "invalid duration unit")
+}
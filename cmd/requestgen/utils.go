@@ -3,9 +3,12 @@ package main
 import (
 	"bytes"
 	"go/format"
+	"go/parser"
+	"go/token"
 	"os"
 
 	"github.com/sirupsen/logrus"
+	"golang.org/x/tools/go/ast/astutil"
 )
 
 // isDirectory reports whether the named file is a directory.
@@ -34,3 +37,34 @@ func formatBuffer(buf bytes.Buffer) []byte {
 
 	return src
 }
+
+// dropUnusedImports removes the given imports from the generated source when
+// the generated code does not reference them, e.g., the requestgen package is
+// only used by the Do method and the parameter validation.
+func dropUnusedImports(src []byte, paths ...string) []byte {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "", src, parser.ParseComments)
+	if err != nil {
+		// leave the invalid source as it is, the user can compile it to see the error
+		return src
+	}
+
+	changed := false
+	for _, path := range paths {
+		if !astutil.UsesImport(file, path) {
+			changed = astutil.DeleteImport(fset, file, path) || changed
+		}
+	}
+
+	if !changed {
+		return src
+	}
+
+	var buf bytes.Buffer
+	if err := format.Node(&buf, fset, file); err != nil {
+		logrus.Errorf("internal error: unable to format the generated code: %s", err)
+		return src
+	}
+
+	return buf.Bytes()
+}
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_dropUnusedImports(t *testing.T) {
+	src := []byte(`package api
+
+import (
+	"fmt"
+	"github.com/c9s/requestgen"
+)
+
+func (q *QueryOrderRequest) String() string {
+	return fmt.Sprintf("%v", q.id)
+}
+`)
+
+	out := string(dropUnusedImports(src, "github.com/c9s/requestgen"))
+	assert.NotContains(t, out, `"github.com/c9s/requestgen"`)
+	assert.Contains(t, out, `"fmt"`)
+
+	used := []byte(`package api
+
+import (
+	"github.com/c9s/requestgen"
+)
+
+func (q *QueryOrderRequest) Validate() error {
+	return requestgen.NewValidationError("id", "id is required")
+}
+`)
+	assert.Equal(t, string(used), string(dropUnusedImports(used, "github.com/c9s/requestgen")))
+}
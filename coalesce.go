@@ -0,0 +1,103 @@
+package requestgen
+
+import (
+	"context"
+	"net/http"
+	"sync"
+)
+
+// coalesceGroup shares the result of a call among the concurrent calls with the same key
+type coalesceGroup struct {
+	mu    sync.Mutex
+	calls map[string]*coalescedCall
+}
+
+type coalescedCall struct {
+	done     chan struct{}
+	response *Response
+	err      error
+
+	// canceled is true if the context of the caller who made the call was done,
+	// so that the waiting callers make the call again instead of sharing the context error
+	canceled bool
+}
+
+// do calls fn once for the concurrent calls with the same key, the callers get the same response and error.
+// A waiting caller returns when its own context is done, and makes the call again if the context of the caller
+// who made the call was done.
+func (g *coalesceGroup) do(ctx context.Context, key string, fn func() (*Response, error)) (*Response, error) {
+	for {
+		g.mu.Lock()
+		if g.calls == nil {
+			g.calls = make(map[string]*coalescedCall)
+		}
+
+		call, ok := g.calls[key]
+		if !ok {
+			break
+		}
+
+		g.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+
+		case <-call.done:
+		}
+
+		if !call.canceled {
+			return call.response, call.err
+		}
+	}
+
+	call := &coalescedCall{done: make(chan struct{})}
+	g.calls[key] = call
+	g.mu.Unlock()
+
+	defer func() {
+		g.mu.Lock()
+		delete(g.calls, key)
+		g.mu.Unlock()
+		close(call.done)
+	}()
+
+	call.response, call.err = fn()
+	call.canceled = call.err != nil && ctx.Err() != nil
+	return call.response, call.err
+}
+
+// coalesceKey returns the coalescing key of the request, false is returned if the request should not be coalesced.
+// Only the GET requests are coalesced, when CoalesceRequests is set or the request meta asks for it.
+func (c *BaseAPIClient) coalesceKey(req *http.Request) (string, bool) {
+	if req.Method != http.MethodGet {
+		return "", false
+	}
+
+	if !c.CoalesceRequests {
+		if meta := RequestMetaFromContext(req.Context()); meta == nil || !meta.Coalesce {
+			return "", false
+		}
+	}
+
+	return c.requestKey(req), true
+}
+
+// requestKey identifies the request by the method, the URL and the identity
+func (c *BaseAPIClient) requestKey(req *http.Request) string {
+	return req.Method + " " + req.URL.String() + " " + c.requestIdentity(req)
+}
+
+// requestIdentity returns the authenticated identity of the request,
+// so that the requests of different credentials are not coalesced or cached together.
+func (c *BaseAPIClient) requestIdentity(req *http.Request) string {
+	if c.CoalesceIdentity != nil {
+		return c.CoalesceIdentity(req)
+	}
+
+	if signer, ok := c.Signer.(*HMACSigner); ok && signer.KeyHeader != "" {
+		return req.Header.Get(signer.KeyHeader)
+	}
+
+	return req.Header.Get("Authorization")
+}
@@ -0,0 +1,145 @@
+package requestgen
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func sendConcurrently(t *testing.T, n int, newRequest func(i int) *http.Request, send func(req *http.Request) (*Response, error)) []*Response {
+	responses := make([]*Response, n)
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		req := newRequest(i)
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			response, err := send(req)
+			assert.NoError(t, err)
+			responses[i] = response
+		}(i)
+	}
+	wg.Wait()
+	return responses
+}
+
+func TestBaseAPIClient_CoalesceRequests(t *testing.T) {
+	var calls int32
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		time.Sleep(100 * time.Millisecond)
+		w.Write([]byte(`{"price":"100"}`))
+	})
+	client.CoalesceRequests = true
+
+	newRequest := func(method, identity string) *http.Request {
+		req, err := client.NewRequest(context.Background(), method, "/api/v1/ticker", map[string][]string{"symbol": {"BTC-USDT"}}, nil)
+		assert.NoError(t, err)
+		req.Header.Set("Authorization", identity)
+		return req
+	}
+
+	responses := sendConcurrently(t, 5, func(i int) *http.Request {
+		return newRequest("GET", "Bearer token")
+	}, client.SendRequest)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+	for _, response := range responses {
+		assert.Equal(t, responses[0], response)
+	}
+
+	// different identities are not coalesced
+	atomic.StoreInt32(&calls, 0)
+	sendConcurrently(t, 2, func(i int) *http.Request {
+		return newRequest("GET", []string{"Bearer a", "Bearer b"}[i])
+	}, client.SendRequest)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+
+	// only GET requests are coalesced
+	atomic.StoreInt32(&calls, 0)
+	sendConcurrently(t, 2, func(i int) *http.Request {
+		return newRequest("POST", "Bearer token")
+	}, client.SendRequest)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+}
+
+func TestBaseAPIClient_CoalesceRequestMeta(t *testing.T) {
+	var calls int32
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		time.Sleep(100 * time.Millisecond)
+		w.Write([]byte(`{}`))
+	})
+
+	newRequest := func(meta *RequestMeta) func(int) *http.Request {
+		return func(int) *http.Request {
+			req, err := client.NewRequest(WithRequestMeta(context.Background(), meta), "GET", "/api/v1/ticker", nil, nil)
+			assert.NoError(t, err)
+			return req
+		}
+	}
+
+	sendConcurrently(t, 3, newRequest(&RequestMeta{}), client.SendRequest)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&calls))
+
+	atomic.StoreInt32(&calls, 0)
+	sendConcurrently(t, 3, newRequest(&RequestMeta{Coalesce: true}), client.SendRequest)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+}
+
+func TestBaseAPIClient_CoalesceRequests_Canceled(t *testing.T) {
+	var calls int32
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		select {
+		case <-r.Context().Done():
+		case <-time.After(100 * time.Millisecond):
+			w.Write([]byte(`{}`))
+		}
+	})
+	client.CoalesceRequests = true
+
+	send := func(ctx context.Context) error {
+		req, err := client.NewRequest(ctx, "GET", "/api/v1/ticker", nil, nil)
+		assert.NoError(t, err)
+
+		_, err = client.SendRequest(req)
+		return err
+	}
+
+	// the waiting request sends the request itself when the first one is canceled
+	leaderCtx, cancelLeader := context.WithCancel(context.Background())
+	leaderErr := make(chan error, 1)
+	go func() { leaderErr <- send(leaderCtx) }()
+
+	time.Sleep(20 * time.Millisecond)
+	followerErr := make(chan error, 1)
+	go func() { followerErr <- send(context.Background()) }()
+
+	time.Sleep(20 * time.Millisecond)
+	cancelLeader()
+
+	assert.True(t, errors.Is(<-leaderErr, context.Canceled))
+	assert.NoError(t, <-followerErr)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+
+	// the waiting request returns when its own context is done
+	atomic.StoreInt32(&calls, 0)
+	go func() { leaderErr <- send(context.Background()) }()
+
+	time.Sleep(20 * time.Millisecond)
+	followerCtx, cancelFollower := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancelFollower()
+
+	start := time.Now()
+	assert.True(t, errors.Is(send(followerCtx), context.DeadlineExceeded))
+	assert.True(t, time.Since(start) < 60*time.Millisecond)
+
+	assert.NoError(t, <-leaderErr)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+}
@@ -0,0 +1,108 @@
+package requestgen
+
+import (
+	"bufio"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// defaultAcceptEncoding is the Accept-Encoding header value sent when BaseAPIClient.EnableCompression is on
+const defaultAcceptEncoding = "gzip, deflate"
+
+// countingReader counts the bytes read from the underlying reader
+type countingReader struct {
+	reader io.Reader
+	n      int64
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	r.n += int64(n)
+	return n, err
+}
+
+// decodedBody reads the decoded content and closes both the decoder and the original body
+type decodedBody struct {
+	io.Reader
+
+	decoder io.Closer
+	body    io.Closer
+}
+
+func (b *decodedBody) Close() error {
+	if b.decoder != nil {
+		_ = b.decoder.Close()
+	}
+	return b.body.Close()
+}
+
+// decodeContentEncoding replaces the body of the gzip or deflate encoded response with the decoded content.
+// It returns the counter of the compressed bytes, or nil if the response body is not encoded.
+func decodeContentEncoding(r *http.Response) (*countingReader, error) {
+	encoding := strings.ToLower(strings.TrimSpace(r.Header.Get("Content-Encoding")))
+	if encoding != "gzip" && encoding != "deflate" {
+		return nil, nil
+	}
+
+	if isBodilessResponse(r) {
+		return nil, nil
+	}
+
+	counter := &countingReader{reader: r.Body}
+	body := &decodedBody{body: r.Body}
+	buffered := bufio.NewReader(counter)
+
+	_, peekErr := buffered.Peek(1)
+
+	switch {
+	case peekErr == io.EOF:
+		// an empty stream is an empty body, not a truncated one
+		body.Reader = buffered
+
+	case encoding == "gzip":
+		reader, err := gzip.NewReader(buffered)
+		if err != nil {
+			return nil, err
+		}
+		body.Reader, body.decoder = reader, reader
+
+	case encoding == "deflate":
+		// deflate is supposed to be zlib wrapped, but some servers send the raw deflate stream
+		if header, err := buffered.Peek(2); err == nil && isZlibHeader(header) {
+			reader, err := zlib.NewReader(buffered)
+			if err != nil {
+				return nil, err
+			}
+			body.Reader, body.decoder = reader, reader
+		} else {
+			reader := flate.NewReader(buffered)
+			body.Reader, body.decoder = reader, reader
+		}
+	}
+
+	// mark the response as decoded, just like what http.Transport does
+	r.Body = body
+	r.Header.Del("Content-Encoding")
+	r.Header.Del("Content-Length")
+	r.ContentLength = -1
+	r.Uncompressed = true
+	return counter, nil
+}
+
+// isBodilessResponse reports whether the response carries no body regardless of its Content-Encoding header
+func isBodilessResponse(r *http.Response) bool {
+	if r.Request != nil && r.Request.Method == http.MethodHead {
+		return true
+	}
+
+	return r.StatusCode == http.StatusNoContent || r.StatusCode == http.StatusNotModified
+}
+
+func isZlibHeader(header []byte) bool {
+	cmf, flg := header[0], header[1]
+	return cmf&0x0f == 8 && (uint16(cmf)<<8|uint16(flg))%31 == 0
+}
@@ -0,0 +1,86 @@
+package requestgen
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBaseAPIClient_EnableCompression(t *testing.T) {
+	payload := `{"data":"` + strings.Repeat("a", 1000) + `"}`
+
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if !strings.Contains(r.Header.Get("Accept-Encoding"), "gzip") {
+			w.Write([]byte(payload))
+			return
+		}
+
+		w.Header().Set("Content-Encoding", "gzip")
+		gw := gzip.NewWriter(w)
+		gw.Write([]byte(payload))
+		gw.Close()
+	})
+	client.EnableCompression = true
+
+	req, err := client.NewRequest(context.Background(), "GET", "/data", nil, nil)
+	assert.NoError(t, err)
+
+	resp, err := client.SendRequest(req)
+	if assert.NoError(t, err) {
+		assert.Equal(t, payload, resp.String())
+		assert.True(t, resp.CompressedSize > 0)
+		assert.True(t, resp.CompressedSize < int64(len(payload)))
+		assert.Empty(t, resp.Header.Get("Content-Encoding"))
+	}
+}
+
+func TestNewResponse_Deflate(t *testing.T) {
+	payload := []byte(`{"data":"deflate"}`)
+
+	var zlibBuf, flateBuf bytes.Buffer
+	zw := zlib.NewWriter(&zlibBuf)
+	zw.Write(payload)
+	zw.Close()
+
+	fw, _ := flate.NewWriter(&flateBuf, flate.DefaultCompression)
+	fw.Write(payload)
+	fw.Close()
+
+	for name, encoded := range map[string][]byte{"zlib": zlibBuf.Bytes(), "raw": flateBuf.Bytes()} {
+		resp, err := NewResponse(&http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{"Content-Encoding": []string{"deflate"}},
+			Body:       io.NopCloser(bytes.NewReader(encoded)),
+		})
+		if assert.NoError(t, err, name) {
+			assert.Equal(t, payload, resp.Body, name)
+			assert.Equal(t, int64(len(encoded)), resp.CompressedSize, name)
+		}
+	}
+}
+
+func TestNewResponse_EncodedEmptyBody(t *testing.T) {
+	for _, resp := range []*http.Response{
+		{StatusCode: http.StatusNoContent, ContentLength: -1},
+		{StatusCode: http.StatusNotModified, ContentLength: -1},
+		{StatusCode: http.StatusOK, ContentLength: -1, Request: &http.Request{Method: http.MethodHead}},
+		{StatusCode: http.StatusOK, ContentLength: 0},
+		{StatusCode: http.StatusOK, ContentLength: -1},
+	} {
+		resp.Header = http.Header{"Content-Encoding": []string{"gzip"}}
+		resp.Body = io.NopCloser(bytes.NewReader(nil))
+
+		r, err := NewResponse(resp)
+		if assert.NoError(t, err, resp.StatusCode) {
+			assert.Empty(t, r.Body, resp.StatusCode)
+		}
+	}
+}
@@ -0,0 +1,241 @@
+package requestgen
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"mime"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Decoder decodes the response body into the value pointed to by v
+type Decoder interface {
+	Decode(data []byte, v interface{}) error
+}
+
+// DecoderFunc is an adapter to allow the use of ordinary functions as Decoder
+type DecoderFunc func(data []byte, v interface{}) error
+
+func (f DecoderFunc) Decode(data []byte, v interface{}) error {
+	return f(data, v)
+}
+
+const (
+	MediaTypeJSON = "application/json"
+	MediaTypeXML  = "application/xml"
+	MediaTypeCSV  = "text/csv"
+	MediaTypeText = "text/plain"
+)
+
+var (
+	JSONDecoder = DecoderFunc(json.Unmarshal)
+	XMLDecoder  = DecoderFunc(xml.Unmarshal)
+	CSVDecoder  = DecoderFunc(decodeCSV)
+	TextDecoder = DecoderFunc(decodeText)
+)
+
+var decoderRegistry = struct {
+	sync.RWMutex
+	decoders map[string]Decoder
+}{
+	decoders: map[string]Decoder{
+		MediaTypeJSON: JSONDecoder,
+		"text/json":   JSONDecoder,
+		MediaTypeXML:  XMLDecoder,
+		"text/xml":    XMLDecoder,
+		MediaTypeCSV:  CSVDecoder,
+		MediaTypeText: TextDecoder,
+	},
+}
+
+// RegisterDecoder registers the decoder of the media type, e.g. "application/x-protobuf".
+// The decoder of a registered media type is replaced.
+func RegisterDecoder(mediaType string, decoder Decoder) {
+	decoderRegistry.Lock()
+	decoderRegistry.decoders[strings.ToLower(mediaType)] = decoder
+	decoderRegistry.Unlock()
+}
+
+// LookupDecoder finds the decoder of the content type, the parameters like charset are ignored.
+// The structured syntax suffixes "+json" and "+xml" fall back to the JSON and XML decoders.
+func LookupDecoder(contentType string) (Decoder, bool) {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return nil, false
+	}
+
+	decoderRegistry.RLock()
+	decoder, ok := decoderRegistry.decoders[mediaType]
+	decoderRegistry.RUnlock()
+	if ok {
+		return decoder, true
+	}
+
+	switch {
+	case strings.HasSuffix(mediaType, "+json"):
+		return JSONDecoder, true
+	case strings.HasSuffix(mediaType, "+xml"):
+		return XMLDecoder, true
+	}
+
+	return nil, false
+}
+
+// decodeText decodes the text body into *string or *[]byte.
+// Some API servers send JSON with the text/plain content type, so other values are decoded as JSON.
+func decodeText(data []byte, v interface{}) error {
+	switch o := v.(type) {
+	case *string:
+		*o = string(data)
+	case *[]byte:
+		*o = append((*o)[:0], data...)
+	default:
+		return json.Unmarshal(data, v)
+	}
+
+	return nil
+}
+
+var textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+
+// decodeCSV decodes the CSV body with a header row into a pointer to a slice of struct or [][]string.
+// The columns are mapped to the struct fields by the csv tag, the json tag or the field name (case-insensitive).
+func decodeCSV(data []byte, v interface{}) error {
+	records, err := csv.NewReader(bytes.NewReader(data)).ReadAll()
+	if err != nil {
+		return err
+	}
+
+	if o, ok := v.(*[][]string); ok {
+		*o = records
+		return nil
+	}
+
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.Elem().Kind() != reflect.Slice {
+		return fmt.Errorf("csv: can not decode into %T, a pointer to slice is expected", v)
+	}
+
+	slice := rv.Elem()
+	elemType := slice.Type().Elem()
+	isPtr := elemType.Kind() == reflect.Ptr
+	if isPtr {
+		elemType = elemType.Elem()
+	}
+
+	if elemType.Kind() != reflect.Struct {
+		return fmt.Errorf("csv: can not decode into %T, a slice of struct is expected", v)
+	}
+
+	slice.SetLen(0)
+	if len(records) == 0 {
+		return nil
+	}
+
+	columns := csvColumnFields(elemType, records[0])
+	for line, record := range records[1:] {
+		elem := reflect.New(elemType).Elem()
+		for col, value := range record {
+			if col >= len(columns) || columns[col] < 0 {
+				continue
+			}
+
+			if err := setCSVValue(elem.Field(columns[col]), value); err != nil {
+				return fmt.Errorf("csv: line %d column %q: %w", line+2, records[0][col], err)
+			}
+		}
+
+		if isPtr {
+			elem = elem.Addr()
+		}
+
+		slice.Set(reflect.Append(slice, elem))
+	}
+
+	return nil
+}
+
+// csvColumnFields maps the header columns to the struct field indexes, -1 means the column is not mapped
+func csvColumnFields(structType reflect.Type, header []string) []int {
+	names := map[string]int{}
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name := field.Name
+		if tag, ok := field.Tag.Lookup("csv"); ok {
+			name = strings.Split(tag, ",")[0]
+		} else if tag, ok := field.Tag.Lookup("json"); ok && tag != "" {
+			name = strings.Split(tag, ",")[0]
+		}
+
+		if name == "-" {
+			continue
+		}
+
+		names[strings.ToLower(name)] = i
+	}
+
+	columns := make([]int, len(header))
+	for i, column := range header {
+		if index, ok := names[strings.ToLower(strings.TrimSpace(column))]; ok {
+			columns[i] = index
+		} else {
+			columns[i] = -1
+		}
+	}
+
+	return columns
+}
+
+func setCSVValue(field reflect.Value, value string) error {
+	if field.CanAddr() && field.Addr().Type().Implements(textUnmarshalerType) {
+		return field.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(value))
+	}
+
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(value)
+
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+		field.SetBool(b)
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(value, 10, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetInt(i)
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u, err := strconv.ParseUint(value, 10, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetUint(u)
+
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(value, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetFloat(f)
+
+	default:
+		return fmt.Errorf("unsupported field type %s", field.Type())
+	}
+
+	return nil
+}
@@ -0,0 +1,84 @@
+package requestgen
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLookupDecoder(t *testing.T) {
+	for _, contentType := range []string{
+		"application/json; charset=UTF-8",
+		"application/problem+json",
+		"text/xml",
+		"application/atom+xml",
+		"text/csv",
+		"text/plain; charset=utf-8",
+	} {
+		decoder, ok := LookupDecoder(contentType)
+		assert.True(t, ok, contentType)
+		assert.NotNil(t, decoder, contentType)
+	}
+
+	_, ok := LookupDecoder("text/html")
+	assert.False(t, ok)
+
+	RegisterDecoder("application/x-custom", DecoderFunc(func(data []byte, v interface{}) error {
+		*(v.(*string)) = "custom:" + string(data)
+		return nil
+	}))
+
+	resp := &Response{
+		Response: &http.Response{StatusCode: 200, Header: http.Header{"Content-Type": []string{"application/x-custom"}}},
+		Body:     []byte("data"),
+	}
+
+	var s string
+	assert.NoError(t, resp.Decode(&s))
+	assert.Equal(t, "custom:data", s)
+}
+
+func TestResponse_Decode(t *testing.T) {
+	newResponse := func(contentType, body string) *Response {
+		header := http.Header{}
+		if contentType != "" {
+			header.Set("Content-Type", contentType)
+		}
+		return &Response{Response: &http.Response{StatusCode: 200, Header: header}, Body: []byte(body)}
+	}
+
+	var text string
+	assert.NoError(t, newResponse("text/plain", "pong").Decode(&text))
+	assert.Equal(t, "pong", text)
+
+	// JSON sent with the text/plain content type
+	var obj map[string]string
+	assert.NoError(t, newResponse("text/plain", `{"a":"b"}`).Decode(&obj))
+	assert.Equal(t, "b", obj["a"])
+
+	// missing content type falls back to JSON
+	assert.NoError(t, newResponse("", `{"c":"d"}`).Decode(&obj))
+	assert.Equal(t, "d", obj["c"])
+
+	type row struct {
+		ID     int     `csv:"id"`
+		Name   string  `json:"name"`
+		Price  float64 // matched by the field name
+		Active bool    `csv:"active"`
+	}
+
+	var rows []row
+	assert.NoError(t, newResponse("text/csv", "id,name,price,active,ignored\n1,foo,1.5,true,x\n2,bar,2,false,y\n").Decode(&rows))
+	assert.Equal(t, []row{{1, "foo", 1.5, true}, {2, "bar", 2, false}}, rows)
+
+	var records [][]string
+	assert.NoError(t, newResponse("text/csv", "a,b\n1,2\n").Decode(&records))
+	assert.Equal(t, [][]string{{"a", "b"}, {"1", "2"}}, records)
+
+	assert.Error(t, newResponse("text/csv", "id\nabc\n").Decode(&rows))
+
+	assert.True(t, newResponse("application/json; charset=UTF-8", "").IsJSON())
+	assert.True(t, newResponse("application/vnd.api+json", "").IsJSON())
+	assert.False(t, newResponse("text/html", "").IsJSON())
+}
@@ -0,0 +1,100 @@
+package requestgen
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+)
+
+// ValidationError is returned by the generated parameter builder methods when a parameter is missing or invalid
+type ValidationError struct {
+	// Parameter is the parameter key
+	Parameter string
+
+	Message string
+}
+
+// NewValidationError creates a ValidationError of the given parameter with the formatted message
+func NewValidationError(parameter string, format string, args ...interface{}) error {
+	return &ValidationError{Parameter: parameter, Message: fmt.Sprintf(format, args...)}
+}
+
+func (e *ValidationError) Error() string {
+	return e.Message
+}
+
+// StatusCode returns the HTTP status code of the error response, 0 is returned if err is not an *ErrResponse
+func StatusCode(err error) int {
+	var errResponse *ErrResponse
+	if errors.As(err, &errResponse) && errResponse.Response != nil && errResponse.Response.Response != nil {
+		return errResponse.StatusCode
+	}
+
+	return 0
+}
+
+// IsRateLimited reports whether the request was rejected by the rate limit of the API server (429)
+func IsRateLimited(err error) bool {
+	return StatusCode(err) == http.StatusTooManyRequests
+}
+
+// IsAuthError reports whether the request was rejected because of the credential or the signature (401 and 403)
+func IsAuthError(err error) bool {
+	switch StatusCode(err) {
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return true
+	}
+
+	return false
+}
+
+// IsServerError reports whether the API server responded with a 5xx status code
+func IsServerError(err error) bool {
+	return StatusCode(err) >= 500
+}
+
+// IsTimeout reports whether the request timed out, including the network timeouts and the context deadline
+func IsTimeout(err error) bool {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+// IsValidationError reports whether err is a parameter validation error from the generated request methods
+func IsValidationError(err error) bool {
+	var validationErr *ValidationError
+	return errors.As(err, &validationErr)
+}
+
+// IsRetryable reports whether the request might succeed if it's sent again,
+// it's also the default retry decision of ExponentialBackoff.
+// Transient server errors (408, 429, 500, 502, 503 and 504), timeouts and network errors are retryable,
+// canceled requests and validation errors are not.
+func IsRetryable(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) || IsValidationError(err) {
+		return false
+	}
+
+	if code := StatusCode(err); code != 0 {
+		switch code {
+		case http.StatusRequestTimeout,
+			http.StatusTooManyRequests,
+			http.StatusInternalServerError,
+			http.StatusBadGateway,
+			http.StatusServiceUnavailable,
+			http.StatusGatewayTimeout:
+			return true
+		}
+
+		return false
+	}
+
+	// the errors returned from http.Client are *url.Error, which implements net.Error
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
@@ -0,0 +1,54 @@
+package requestgen
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestErrResponse(statusCode int) error {
+	return &ErrResponse{Response: &Response{Response: &http.Response{StatusCode: statusCode}}}
+}
+
+func TestErrorClassification(t *testing.T) {
+	rateLimited := newTestErrResponse(http.StatusTooManyRequests)
+	assert.Equal(t, http.StatusTooManyRequests, StatusCode(rateLimited))
+	assert.True(t, IsRateLimited(rateLimited))
+	assert.True(t, IsRetryable(rateLimited))
+	assert.False(t, IsAuthError(rateLimited))
+
+	wrapped := fmt.Errorf("place order: %w", newTestErrResponse(http.StatusUnauthorized))
+	assert.Equal(t, http.StatusUnauthorized, StatusCode(wrapped))
+	assert.True(t, IsAuthError(wrapped))
+	assert.False(t, IsRetryable(wrapped))
+
+	serverError := newTestErrResponse(http.StatusServiceUnavailable)
+	assert.True(t, IsServerError(serverError))
+	assert.True(t, IsRetryable(serverError))
+	assert.False(t, IsRetryable(newTestErrResponse(http.StatusNotImplemented)))
+
+	networkErr := &url.Error{Op: "Get", URL: "https://api.example.com", Err: errors.New("connection reset by peer")}
+	assert.Equal(t, 0, StatusCode(networkErr))
+	assert.True(t, IsRetryable(networkErr))
+	assert.False(t, IsTimeout(networkErr))
+
+	timeoutErr := &url.Error{Op: "Get", URL: "https://api.example.com", Err: context.DeadlineExceeded}
+	assert.True(t, IsTimeout(timeoutErr))
+	assert.True(t, IsRetryable(timeoutErr))
+
+	canceledErr := &url.Error{Op: "Get", URL: "https://api.example.com", Err: context.Canceled}
+	assert.False(t, IsRetryable(canceledErr))
+
+	validationErr := NewValidationError("symbol", "symbol is required, empty string given")
+	assert.True(t, IsValidationError(validationErr))
+	assert.False(t, IsRetryable(validationErr))
+	assert.Equal(t, "symbol is required, empty string given", validationErr.Error())
+
+	assert.False(t, IsRetryable(nil))
+	assert.Equal(t, 0, StatusCode(nil))
+}
@@ -0,0 +1,18 @@
+package api
+
+import "github.com/c9s/requestgen"
+
+type TokenResponse struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	ExpiresIn   int    `json:"expires_in"`
+}
+
+//go:generate go run ../../cmd/requestgen -type AccessTokenRequest -url /oauth/token -method POST -bodyEncoding form -responseType .TokenResponse
+type AccessTokenRequest struct {
+	client requestgen.APIClient
+
+	grantType    string `param:"grant_type,required" default:"client_credentials"`
+	clientID     string `param:"client_id,required"`
+	clientSecret string `param:"client_secret,required"`
+}
@@ -0,0 +1,265 @@
+// Code generated by "requestgen -type AccessTokenRequest -url /oauth/token -method POST -bodyEncoding form -responseType .TokenResponse"; DO NOT EDIT.
+
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/c9s/requestgen"
+	"net/url"
+	"reflect"
+	"regexp"
+	"sync"
+)
+
+/*
+ * GrantType sets
+ */
+func (a *AccessTokenRequest) GrantType(grantType string) *AccessTokenRequest {
+	a.grantType = grantType
+	return a
+}
+
+/*
+ * ClientID sets
+ */
+func (a *AccessTokenRequest) ClientID(clientID string) *AccessTokenRequest {
+	a.clientID = clientID
+	return a
+}
+
+/*
+ * ClientSecret sets
+ */
+func (a *AccessTokenRequest) ClientSecret(clientSecret string) *AccessTokenRequest {
+	a.clientSecret = clientSecret
+	return a
+}
+
+// GetQueryParameters builds and checks the query parameters and returns url.Values
+func (a *AccessTokenRequest) GetQueryParameters() (url.Values, error) {
+	var params = map[string]interface{}{}
+
+	query := url.Values{}
+	for _k, _v := range params {
+		if a.isVarSlice(_v) {
+			a.iterateSlice(_v, func(it interface{}) {
+				query.Add(_k+"[]", fmt.Sprintf("%v", it))
+			})
+		} else {
+			query.Add(_k, fmt.Sprintf("%v", _v))
+		}
+	}
+
+	return query, nil
+}
+
+// GetParameters builds and checks the parameters and return the result in a map object
+func (a *AccessTokenRequest) GetParameters() (map[string]interface{}, error) {
+	var params = map[string]interface{}{}
+	// check grantType field -> json key grant_type
+	grantType := a.grantType
+
+	// TEMPLATE check-required
+	if len(grantType) == 0 {
+
+		grantType = "client_credentials"
+	}
+	// END TEMPLATE check-required
+
+	// assign parameter of grantType
+	params["grant_type"] = grantType
+	// check clientID field -> json key client_id
+	clientID := a.clientID
+
+	// TEMPLATE check-required
+	if len(clientID) == 0 {
+		return nil, requestgen.NewValidationError("client_id", "client_id is required, empty string given")
+	}
+	// END TEMPLATE check-required
+
+	// assign parameter of clientID
+	params["client_id"] = clientID
+	// check clientSecret field -> json key client_secret
+	clientSecret := a.clientSecret
+
+	// TEMPLATE check-required
+	if len(clientSecret) == 0 {
+		return nil, requestgen.NewValidationError("client_secret", "client_secret is required, empty string given")
+	}
+	// END TEMPLATE check-required
+
+	// assign parameter of clientSecret
+	params["client_secret"] = clientSecret
+
+	return params, nil
+}
+
+// GetParametersQuery converts the parameters from GetParameters into the url.Values format
+func (a *AccessTokenRequest) GetParametersQuery() (url.Values, error) {
+	query := url.Values{}
+
+	params, err := a.GetParameters()
+	if err != nil {
+		return query, err
+	}
+
+	for _k, _v := range params {
+		if a.isVarSlice(_v) {
+			a.iterateSlice(_v, func(it interface{}) {
+				query.Add(_k+"[]", fmt.Sprintf("%v", it))
+			})
+		} else {
+			query.Add(_k, fmt.Sprintf("%v", _v))
+		}
+	}
+
+	return query, nil
+}
+
+// GetParametersJSON converts the parameters from GetParameters into the JSON format
+func (a *AccessTokenRequest) GetParametersJSON() ([]byte, error) {
+	params, err := a.GetParameters()
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(params)
+}
+
+// GetSlugParameters builds and checks the slug parameters and return the result in a map object
+func (a *AccessTokenRequest) GetSlugParameters() (map[string]interface{}, error) {
+	var params = map[string]interface{}{}
+
+	return params, nil
+}
+
+var AccessTokenRequestSlugReCache sync.Map
+
+func (a *AccessTokenRequest) applySlugsToUrl(url string, slugs map[string]string) string {
+	for _k, _v := range slugs {
+		var needleRE *regexp.Regexp
+
+		if cached, ok := AccessTokenRequestSlugReCache.Load(_k); ok {
+			needleRE = cached.(*regexp.Regexp)
+		} else {
+			needleRE = regexp.MustCompile(":" + _k + "\\b")
+			AccessTokenRequestSlugReCache.Store(_k, needleRE)
+		}
+
+		url = needleRE.ReplaceAllString(url, _v)
+	}
+
+	return url
+}
+
+func (a *AccessTokenRequest) iterateSlice(slice interface{}, _f func(it interface{})) {
+	sliceValue := reflect.ValueOf(slice)
+	for _i := 0; _i < sliceValue.Len(); _i++ {
+		it := sliceValue.Index(_i).Interface()
+		_f(it)
+	}
+}
+
+func (a *AccessTokenRequest) isVarSlice(_v interface{}) bool {
+	rt := reflect.TypeOf(_v)
+	switch rt.Kind() {
+	case reflect.Slice:
+		return true
+	}
+	return false
+}
+
+func (a *AccessTokenRequest) GetSlugsMap() (map[string]string, error) {
+	slugs := map[string]string{}
+	params, err := a.GetSlugParameters()
+	if err != nil {
+		return slugs, nil
+	}
+
+	for _k, _v := range params {
+		slugs[_k] = fmt.Sprintf("%v", _v)
+	}
+
+	return slugs, nil
+}
+
+// GetPath returns the request path of the API
+func (a *AccessTokenRequest) GetPath() string {
+	return "/oauth/token"
+}
+
+// Do generates the request object and send the request object to the API endpoint
+func (a *AccessTokenRequest) Do(ctx context.Context) (_ *TokenResponse, err error) {
+	meta := &requestgen.RequestMeta{
+		Name:         "AccessTokenRequest",
+		Method:       "POST",
+		PathTemplate: a.GetPath(),
+	}
+	ctx = requestgen.WithRequestMeta(ctx, meta)
+
+	ctx, span := requestgen.StartRequestSpan(ctx, meta)
+	defer func() {
+		requestgen.EndSpan(span, err)
+	}()
+
+	// url.Values params are sent as a form-urlencoded body
+	params, err := a.GetParametersQuery()
+	if err != nil {
+		return nil, err
+	}
+	query := url.Values{}
+
+	var apiURL string
+
+	apiURL = a.GetPath()
+
+	req, err := a.client.NewRequest(ctx, "POST", apiURL, query, params)
+	if err != nil {
+		return nil, err
+	}
+
+	span.AddEvent("build_request")
+
+	response, err := a.client.SendRequest(req)
+	if response != nil && response.Response != nil {
+		span.SetAttributes(requestgen.Attr(requestgen.AttrStatusCode, response.StatusCode))
+	}
+
+	span.AddEvent("send_request")
+	if err != nil {
+		return nil, err
+	}
+
+	var apiResponse TokenResponse
+
+	type responseUnmarshaler interface {
+		Unmarshal(data []byte) error
+	}
+
+	if unmarshaler, ok := interface{}(&apiResponse).(responseUnmarshaler); ok {
+		if err := unmarshaler.Unmarshal(response.Body); err != nil {
+			return nil, err
+		}
+	} else {
+		// The decoder is chosen by the content type of the response, JSON is used if the content type is unknown,
+		// since some API servers might not send the correct content type header.
+		if err := response.Decode(&apiResponse); err != nil {
+			return nil, err
+		}
+	}
+
+	span.AddEvent("decode_response")
+
+	type responseValidator interface {
+		Validate() error
+	}
+
+	if validator, ok := interface{}(&apiResponse).(responseValidator); ok {
+		if err := validator.Validate(); err != nil {
+			return nil, err
+		}
+	}
+	return &apiResponse, nil
+}
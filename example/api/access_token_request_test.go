@@ -0,0 +1,40 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/requestgen"
+	"github.com/c9s/requestgen/mocktest"
+)
+
+func TestAccessTokenRequest_FormBody(t *testing.T) {
+	transport := mocktest.NewTransport(t)
+	route := transport.POST("/oauth/token").
+		WithHeader("Content-Type", requestgen.FormContentType).
+		RespondJSON(http.StatusOK, map[string]interface{}{
+			"access_token": "token",
+			"token_type":   "Bearer",
+			"expires_in":   3600,
+		})
+
+	client := NewClient()
+	client.HttpClient.Transport = transport
+
+	req := &AccessTokenRequest{client: client}
+	token, err := req.ClientID("my-client").ClientSecret("my-secret").Do(context.Background())
+	if assert.NoError(t, err) {
+		assert.Equal(t, "token", token.AccessToken)
+		assert.Equal(t, 3600, token.ExpiresIn)
+	}
+
+	if captured := route.LastRequest(); assert.NotNil(t, captured) {
+		assert.NoError(t, captured.ParseForm())
+		assert.Equal(t, "client_credentials", captured.PostForm.Get("grant_type"))
+		assert.Equal(t, "my-client", captured.PostForm.Get("client_id"))
+		assert.Equal(t, "my-secret", captured.PostForm.Get("client_secret"))
+	}
+}
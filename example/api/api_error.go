@@ -0,0 +1,18 @@
+package api
+
+import "fmt"
+
+// APIError is the error envelope of the API, e.g. {"code":"400100","msg":"account balance insufficient"}
+type APIError struct {
+	Code    string `json:"code"`
+	Message string `json:"msg"`
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("api error %s: %s", e.Code, e.Message)
+}
+
+// IsError reports the failure carried by a successful response, code 200000 means success
+func (e *APIError) IsError() bool {
+	return e.Code != "" && e.Code != "200000"
+}
@@ -2,16 +2,9 @@ package api
 
 
 import (
-	"bytes"
 	"context"
-	"crypto/hmac"
-	"crypto/sha256"
-	"encoding/base64"
-	"encoding/json"
 	"net/http"
 	"net/url"
-	"strconv"
-	"strings"
 	"time"
 
 	"github.com/pkg/errors"
@@ -51,6 +44,22 @@ func (c *RestClient) Auth(key, secret, passphrase string) {
 	c.Key = key
 	c.Secret = secret
 	c.Passphrase = passphrase
+
+	// the prehash string is timestamp + method + path with query + body
+	signer := &requestgen.HMACSigner{
+		Key:             key,
+		Secret:          secret,
+		Encoding:        requestgen.SignatureEncodingBase64,
+		KeyHeader:       "KC-API-KEY",
+		SignatureHeader: "KC-API-SIGN",
+		TimestampHeader: "KC-API-TIMESTAMP",
+	}
+	signer.Headers = map[string]string{
+		"KC-API-PASSPHRASE":  signer.Sum(passphrase),
+		"KC-API-KEY-VERSION": c.KeyVersion,
+	}
+
+	c.Signer = signer
 }
 
 // NewAuthenticatedRequest creates new http request for authenticated routes.
@@ -63,80 +72,14 @@ func (c *RestClient) NewAuthenticatedRequest(ctx context.Context, method, refURL
 		return nil, errors.New("empty api secret")
 	}
 
-	rel, err := url.Parse(refURL)
+	req, err := c.BaseAPIClient.NewAuthenticatedRequest(ctx, method, refURL, params, payload)
 	if err != nil {
 		return nil, err
 	}
 
-	if params != nil {
-		rel.RawQuery = params.Encode()
+	if req.Header.Get("Content-Type") == "" {
+		req.Header.Set("Content-Type", "application/json")
 	}
-
-	pathURL := c.BaseURL.ResolveReference(rel)
-	path := pathURL.Path
-	if rel.RawQuery != "" {
-		path += "?" + rel.RawQuery
-	}
-
-	body, err := castPayload(payload)
-	if err != nil {
-		return nil, err
-	}
-
-	req, err := http.NewRequestWithContext(ctx, method, pathURL.String(), bytes.NewReader(body))
-	if err != nil {
-		return nil, err
-	}
-
-	req.Header.Add("Content-Type", "application/json")
 	req.Header.Add("Accept", "application/json")
-
-	// Build authentication headers
-	c.attachAuthHeaders(req, method, path, body)
 	return req, nil
 }
-
-func (c *RestClient) attachAuthHeaders(req *http.Request, method string, path string, body []byte) {
-	// Set location to UTC so that it outputs "2020-12-08T09:08:57.715Z"
-	t := time.Now().In(time.UTC)
-	// timestamp := t.Format("2006-01-02T15:04:05.999Z07:00")
-	timestamp := strconv.FormatInt(t.UnixNano()/int64(time.Millisecond), 10)
-	signKey := timestamp + strings.ToUpper(method) + path + string(body)
-	signature := sign(c.Secret, signKey)
-
-	req.Header.Add("KC-API-KEY", c.Key)
-	req.Header.Add("KC-API-SIGN", signature)
-	req.Header.Add("KC-API-TIMESTAMP", timestamp)
-	req.Header.Add("KC-API-PASSPHRASE", sign(c.Secret, c.Passphrase))
-	req.Header.Add("KC-API-KEY-VERSION", c.KeyVersion)
-}
-
-// sign uses sha256 to sign the payload with the given secret
-func sign(secret, payload string) string {
-	var sig = hmac.New(sha256.New, []byte(secret))
-	_, err := sig.Write([]byte(payload))
-	if err != nil {
-		return ""
-	}
-
-	return base64.StdEncoding.EncodeToString(sig.Sum(nil))
-}
-
-func castPayload(payload interface{}) ([]byte, error) {
-	if payload != nil {
-		switch v := payload.(type) {
-		case string:
-			return []byte(v), nil
-
-		case []byte:
-			return v, nil
-
-		default:
-			body, err := json.Marshal(v)
-			return body, err
-		}
-	}
-
-	return nil, nil
-}
-
@@ -0,0 +1,43 @@
+package api
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func kucoinSign(secret, payload string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(payload))
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func TestRestClient_NewAuthenticatedRequest(t *testing.T) {
+	client := NewClient()
+	client.Auth("key", "secret", "passphrase")
+
+	req, err := client.NewAuthenticatedRequest(context.Background(), "POST", "/v1/orders", nil, map[string]string{"symbol": "BTC-USDT"})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	timestamp := req.Header.Get("KC-API-TIMESTAMP")
+	_, err = strconv.ParseInt(timestamp, 10, 64)
+	assert.NoError(t, err)
+
+	assert.Equal(t, "key", req.Header.Get("KC-API-KEY"))
+	assert.Equal(t, kucoinSign("secret", timestamp+"POST/v1/orders"+`{"symbol":"BTC-USDT"}`), req.Header.Get("KC-API-SIGN"))
+	assert.Equal(t, kucoinSign("secret", "passphrase"), req.Header.Get("KC-API-PASSPHRASE"))
+	assert.Equal(t, "2", req.Header.Get("KC-API-KEY-VERSION"))
+	assert.Equal(t, "application/json", req.Header.Get("Content-Type"))
+}
+
+func TestRestClient_NewAuthenticatedRequest_EmptyKey(t *testing.T) {
+	_, err := NewClient().NewAuthenticatedRequest(context.Background(), "GET", "/v1/orders", nil, nil)
+	assert.Error(t, err)
+}
@@ -0,0 +1,21 @@
+package api
+
+import "github.com/c9s/requestgen"
+
+type Withdrawal struct {
+	WithdrawalID string `json:"withdrawalId"`
+}
+
+//go:generate go run ../../cmd/requestgen -type CreateWithdrawalRequest -url /v1/withdrawals -method POST -responseType .Response -responseDataField Data -responseDataType .Withdrawal
+type CreateWithdrawalRequest struct {
+	client requestgen.APIClient
+
+	// idempotencyKey is sent in the Idempotency-Key header, so the withdrawal is not created twice when it's retried
+	idempotencyKey *string `idempotencyKey:"header"`
+
+	currency string `param:"currency,required"`
+
+	address string `param:"address,required"`
+
+	amount string `param:"amount,required"`
+}
@@ -0,0 +1,290 @@
+// Code generated by "requestgen -type CreateWithdrawalRequest -url /v1/withdrawals -method POST -responseType .Response -responseDataField Data -responseDataType .Withdrawal"; DO NOT EDIT.
+
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/c9s/requestgen"
+	"github.com/google/uuid"
+	"net/url"
+	"reflect"
+	"regexp"
+	"sync"
+)
+
+/*
+ * Currency sets
+ */
+func (c *CreateWithdrawalRequest) Currency(currency string) *CreateWithdrawalRequest {
+	c.currency = currency
+	return c
+}
+
+/*
+ * Address sets
+ */
+func (c *CreateWithdrawalRequest) Address(address string) *CreateWithdrawalRequest {
+	c.address = address
+	return c
+}
+
+/*
+ * Amount sets
+ */
+func (c *CreateWithdrawalRequest) Amount(amount string) *CreateWithdrawalRequest {
+	c.amount = amount
+	return c
+}
+
+/*
+ * IdempotencyKey sets idempotencyKey is sent in the Idempotency-Key header, so the withdrawal is not created twice when it's retried
+ */
+func (c *CreateWithdrawalRequest) IdempotencyKey(idempotencyKey string) *CreateWithdrawalRequest {
+	c.idempotencyKey = &idempotencyKey
+	return c
+}
+
+// GetQueryParameters builds and checks the query parameters and returns url.Values
+func (c *CreateWithdrawalRequest) GetQueryParameters() (url.Values, error) {
+	var params = map[string]interface{}{}
+
+	query := url.Values{}
+	for _k, _v := range params {
+		if c.isVarSlice(_v) {
+			c.iterateSlice(_v, func(it interface{}) {
+				query.Add(_k+"[]", fmt.Sprintf("%v", it))
+			})
+		} else {
+			query.Add(_k, fmt.Sprintf("%v", _v))
+		}
+	}
+
+	return query, nil
+}
+
+// GetParameters builds and checks the parameters and return the result in a map object
+func (c *CreateWithdrawalRequest) GetParameters() (map[string]interface{}, error) {
+	var params = map[string]interface{}{}
+	// check currency field -> json key currency
+	currency := c.currency
+
+	// TEMPLATE check-required
+	if len(currency) == 0 {
+		return nil, requestgen.NewValidationError("currency", "currency is required, empty string given")
+	}
+	// END TEMPLATE check-required
+
+	// assign parameter of currency
+	params["currency"] = currency
+	// check address field -> json key address
+	address := c.address
+
+	// TEMPLATE check-required
+	if len(address) == 0 {
+		return nil, requestgen.NewValidationError("address", "address is required, empty string given")
+	}
+	// END TEMPLATE check-required
+
+	// assign parameter of address
+	params["address"] = address
+	// check amount field -> json key amount
+	amount := c.amount
+
+	// TEMPLATE check-required
+	if len(amount) == 0 {
+		return nil, requestgen.NewValidationError("amount", "amount is required, empty string given")
+	}
+	// END TEMPLATE check-required
+
+	// assign parameter of amount
+	params["amount"] = amount
+
+	return params, nil
+}
+
+// GetParametersQuery converts the parameters from GetParameters into the url.Values format
+func (c *CreateWithdrawalRequest) GetParametersQuery() (url.Values, error) {
+	query := url.Values{}
+
+	params, err := c.GetParameters()
+	if err != nil {
+		return query, err
+	}
+
+	for _k, _v := range params {
+		if c.isVarSlice(_v) {
+			c.iterateSlice(_v, func(it interface{}) {
+				query.Add(_k+"[]", fmt.Sprintf("%v", it))
+			})
+		} else {
+			query.Add(_k, fmt.Sprintf("%v", _v))
+		}
+	}
+
+	return query, nil
+}
+
+// GetParametersJSON converts the parameters from GetParameters into the JSON format
+func (c *CreateWithdrawalRequest) GetParametersJSON() ([]byte, error) {
+	params, err := c.GetParameters()
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(params)
+}
+
+// GetSlugParameters builds and checks the slug parameters and return the result in a map object
+func (c *CreateWithdrawalRequest) GetSlugParameters() (map[string]interface{}, error) {
+	var params = map[string]interface{}{}
+
+	return params, nil
+}
+
+var CreateWithdrawalRequestSlugReCache sync.Map
+
+func (c *CreateWithdrawalRequest) applySlugsToUrl(url string, slugs map[string]string) string {
+	for _k, _v := range slugs {
+		var needleRE *regexp.Regexp
+
+		if cached, ok := CreateWithdrawalRequestSlugReCache.Load(_k); ok {
+			needleRE = cached.(*regexp.Regexp)
+		} else {
+			needleRE = regexp.MustCompile(":" + _k + "\\b")
+			CreateWithdrawalRequestSlugReCache.Store(_k, needleRE)
+		}
+
+		url = needleRE.ReplaceAllString(url, _v)
+	}
+
+	return url
+}
+
+func (c *CreateWithdrawalRequest) iterateSlice(slice interface{}, _f func(it interface{})) {
+	sliceValue := reflect.ValueOf(slice)
+	for _i := 0; _i < sliceValue.Len(); _i++ {
+		it := sliceValue.Index(_i).Interface()
+		_f(it)
+	}
+}
+
+func (c *CreateWithdrawalRequest) isVarSlice(_v interface{}) bool {
+	rt := reflect.TypeOf(_v)
+	switch rt.Kind() {
+	case reflect.Slice:
+		return true
+	}
+	return false
+}
+
+func (c *CreateWithdrawalRequest) GetSlugsMap() (map[string]string, error) {
+	slugs := map[string]string{}
+	params, err := c.GetSlugParameters()
+	if err != nil {
+		return slugs, nil
+	}
+
+	for _k, _v := range params {
+		slugs[_k] = fmt.Sprintf("%v", _v)
+	}
+
+	return slugs, nil
+}
+
+// GetIdempotencyKey returns the idempotency key of the request. A new key is generated and kept in idempotencyKey
+// if it's empty, so the retries of the same request object send the same key. The key can be restored with IdempotencyKey.
+func (c *CreateWithdrawalRequest) GetIdempotencyKey() string {
+	if c.idempotencyKey == nil || len(*c.idempotencyKey) == 0 {
+		key := uuid.New().String()
+		c.idempotencyKey = &key
+	}
+
+	return *c.idempotencyKey
+}
+
+// GetPath returns the request path of the API
+func (c *CreateWithdrawalRequest) GetPath() string {
+	return "/v1/withdrawals"
+}
+
+// Do generates the request object and send the request object to the API endpoint
+func (c *CreateWithdrawalRequest) Do(ctx context.Context) (_ *Withdrawal, err error) {
+	meta := &requestgen.RequestMeta{
+		Name:           "CreateWithdrawalRequest",
+		Method:         "POST",
+		PathTemplate:   c.GetPath(),
+		IdempotencyKey: c.GetIdempotencyKey(),
+	}
+	ctx = requestgen.WithRequestMeta(ctx, meta)
+
+	ctx, span := requestgen.StartRequestSpan(ctx, meta)
+	defer func() {
+		requestgen.EndSpan(span, err)
+	}()
+
+	params, err := c.GetParameters()
+	if err != nil {
+		return nil, err
+	}
+	query := url.Values{}
+
+	var apiURL string
+
+	apiURL = c.GetPath()
+
+	req, err := c.client.NewRequest(ctx, "POST", apiURL, query, params)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Idempotency-Key", meta.IdempotencyKey)
+
+	span.AddEvent("build_request")
+
+	response, err := c.client.SendRequest(req)
+	if response != nil && response.Response != nil {
+		span.SetAttributes(requestgen.Attr(requestgen.AttrStatusCode, response.StatusCode))
+	}
+
+	span.AddEvent("send_request")
+	if err != nil {
+		return nil, err
+	}
+
+	var apiResponse Response
+
+	type responseUnmarshaler interface {
+		Unmarshal(data []byte) error
+	}
+
+	if unmarshaler, ok := interface{}(&apiResponse).(responseUnmarshaler); ok {
+		if err := unmarshaler.Unmarshal(response.Body); err != nil {
+			return nil, err
+		}
+	} else {
+		// The decoder is chosen by the content type of the response, JSON is used if the content type is unknown,
+		// since some API servers might not send the correct content type header.
+		if err := response.Decode(&apiResponse); err != nil {
+			return nil, err
+		}
+	}
+
+	span.AddEvent("decode_response")
+
+	type responseValidator interface {
+		Validate() error
+	}
+
+	if validator, ok := interface{}(&apiResponse).(responseValidator); ok {
+		if err := validator.Validate(); err != nil {
+			return nil, err
+		}
+	}
+	var data Withdrawal
+	if err := json.Unmarshal(apiResponse.Data, &data); err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
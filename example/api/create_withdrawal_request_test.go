@@ -0,0 +1,48 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/requestgen/mocktest"
+)
+
+func TestCreateWithdrawalRequest_IdempotencyKey(t *testing.T) {
+	transport := mocktest.NewTransport(t)
+	route := transport.POST("/v1/withdrawals").
+		RespondStatus(http.StatusBadGateway).
+		RespondJSON(http.StatusOK, map[string]interface{}{
+			"code": "200000",
+			"data": map[string]string{"withdrawalId": "w-1"},
+		})
+
+	client := NewClient()
+	client.HttpClient.Transport = transport
+
+	req := &CreateWithdrawalRequest{client: client}
+	req.Currency("USDT").Address("0x0").Amount("100")
+
+	// the caller retries the failed request with the same request object
+	_, err := req.Do(context.Background())
+	assert.Error(t, err)
+
+	withdrawal, err := req.Do(context.Background())
+	if assert.NoError(t, err) {
+		assert.Equal(t, "w-1", withdrawal.WithdrawalID)
+	}
+
+	key := req.GetIdempotencyKey()
+	if assert.Len(t, route.Requests(), 2) {
+		assert.Equal(t, key, route.Requests()[0].Header.Get("Idempotency-Key"))
+		assert.Equal(t, key, route.Requests()[1].Header.Get("Idempotency-Key"))
+	}
+
+	// the key is not a parameter
+	params, err := req.GetParameters()
+	if assert.NoError(t, err) {
+		assert.NotContains(t, params, "idempotencyKey")
+	}
+}
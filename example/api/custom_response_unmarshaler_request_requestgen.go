@@ -6,9 +6,11 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"github.com/c9s/requestgen"
 	"net/url"
 	"reflect"
 	"regexp"
+	"sync"
 )
 
 // GetQueryParameters builds and checks the query parameters and returns url.Values
@@ -17,7 +19,13 @@ func (c *CustomResponseUnmarshalerRequest) GetQueryParameters() (url.Values, err
 
 	query := url.Values{}
 	for _k, _v := range params {
-		query.Add(_k, fmt.Sprintf("%v", _v))
+		if c.isVarSlice(_v) {
+			c.iterateSlice(_v, func(it interface{}) {
+				query.Add(_k+"[]", fmt.Sprintf("%v", it))
+			})
+		} else {
+			query.Add(_k, fmt.Sprintf("%v", _v))
+		}
 	}
 
 	return query, nil
@@ -69,9 +77,19 @@ func (c *CustomResponseUnmarshalerRequest) GetSlugParameters() (map[string]inter
 	return params, nil
 }
 
+var CustomResponseUnmarshalerRequestSlugReCache sync.Map
+
 func (c *CustomResponseUnmarshalerRequest) applySlugsToUrl(url string, slugs map[string]string) string {
 	for _k, _v := range slugs {
-		needleRE := regexp.MustCompile(":" + _k + "\\b")
+		var needleRE *regexp.Regexp
+
+		if cached, ok := CustomResponseUnmarshalerRequestSlugReCache.Load(_k); ok {
+			needleRE = cached.(*regexp.Regexp)
+		} else {
+			needleRE = regexp.MustCompile(":" + _k + "\\b")
+			CustomResponseUnmarshalerRequestSlugReCache.Store(_k, needleRE)
+		}
+
 		url = needleRE.ReplaceAllString(url, _v)
 	}
 
@@ -115,7 +133,18 @@ func (c *CustomResponseUnmarshalerRequest) GetPath() string {
 }
 
 // Do generates the request object and send the request object to the API endpoint
-func (c *CustomResponseUnmarshalerRequest) Do(ctx context.Context) (*CustomUnmarshalerResponse, error) {
+func (c *CustomResponseUnmarshalerRequest) Do(ctx context.Context) (_ *CustomUnmarshalerResponse, err error) {
+	meta := &requestgen.RequestMeta{
+		Name:         "CustomResponseUnmarshalerRequest",
+		Method:       "GET",
+		PathTemplate: c.GetPath(),
+	}
+	ctx = requestgen.WithRequestMeta(ctx, meta)
+
+	ctx, span := requestgen.StartRequestSpan(ctx, meta)
+	defer func() {
+		requestgen.EndSpan(span, err)
+	}()
 
 	// no body params
 	var params interface{}
@@ -130,7 +159,14 @@ func (c *CustomResponseUnmarshalerRequest) Do(ctx context.Context) (*CustomUnmar
 		return nil, err
 	}
 
+	span.AddEvent("build_request")
+
 	response, err := c.client.SendRequest(req)
+	if response != nil && response.Response != nil {
+		span.SetAttributes(requestgen.Attr(requestgen.AttrStatusCode, response.StatusCode))
+	}
+
+	span.AddEvent("send_request")
 	if err != nil {
 		return nil, err
 	}
@@ -146,14 +182,15 @@ func (c *CustomResponseUnmarshalerRequest) Do(ctx context.Context) (*CustomUnmar
 			return nil, err
 		}
 	} else {
-		// The line below checks the content type, however, some API server might not send the correct content type header,
-		// Hence, this is commented for backward compatibility
-		// response.IsJSON()
-		if err := response.DecodeJSON(&apiResponse); err != nil {
+		// The decoder is chosen by the content type of the response, JSON is used if the content type is unknown,
+		// since some API servers might not send the correct content type header.
+		if err := response.Decode(&apiResponse); err != nil {
 			return nil, err
 		}
 	}
 
+	span.AddEvent("decode_response")
+
 	type responseValidator interface {
 		Validate() error
 	}
@@ -6,14 +6,14 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/requestgen/mocktest"
 )
 
 func TestCustomUnmarshalRequest(t *testing.T) {
-	transport := &MockTransport{}
-	transport.GET("/v1/bullet", func(req *http.Request) (*http.Response, error) {
-		return BuildResponseJson(http.StatusOK, map[string]interface{}{
-			"foo": "bar",
-		}), nil
+	transport := mocktest.NewTransport(t)
+	transport.GET("/v1/bullet").RespondJSON(http.StatusOK, map[string]interface{}{
+		"foo": "bar",
 	})
 
 	client := NewClient()
@@ -0,0 +1,8 @@
+package api
+
+import "github.com/c9s/requestgen"
+
+//go:generate go run ../../cmd/requestgen -type GetCurrenciesRequest -url /v1/currencies -method GET -responseType .Response -cacheTTL 5m
+type GetCurrenciesRequest struct {
+	client requestgen.APIClient
+}
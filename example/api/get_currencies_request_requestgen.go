@@ -0,0 +1,205 @@
+// Code generated by "requestgen -type GetCurrenciesRequest -url /v1/currencies -method GET -responseType .Response -cacheTTL 5m"; DO NOT EDIT.
+
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/c9s/requestgen"
+	"net/url"
+	"reflect"
+	"regexp"
+	"sync"
+)
+
+// GetQueryParameters builds and checks the query parameters and returns url.Values
+func (g *GetCurrenciesRequest) GetQueryParameters() (url.Values, error) {
+	var params = map[string]interface{}{}
+
+	query := url.Values{}
+	for _k, _v := range params {
+		if g.isVarSlice(_v) {
+			g.iterateSlice(_v, func(it interface{}) {
+				query.Add(_k+"[]", fmt.Sprintf("%v", it))
+			})
+		} else {
+			query.Add(_k, fmt.Sprintf("%v", _v))
+		}
+	}
+
+	return query, nil
+}
+
+// GetParameters builds and checks the parameters and return the result in a map object
+func (g *GetCurrenciesRequest) GetParameters() (map[string]interface{}, error) {
+	var params = map[string]interface{}{}
+
+	return params, nil
+}
+
+// GetParametersQuery converts the parameters from GetParameters into the url.Values format
+func (g *GetCurrenciesRequest) GetParametersQuery() (url.Values, error) {
+	query := url.Values{}
+
+	params, err := g.GetParameters()
+	if err != nil {
+		return query, err
+	}
+
+	for _k, _v := range params {
+		if g.isVarSlice(_v) {
+			g.iterateSlice(_v, func(it interface{}) {
+				query.Add(_k+"[]", fmt.Sprintf("%v", it))
+			})
+		} else {
+			query.Add(_k, fmt.Sprintf("%v", _v))
+		}
+	}
+
+	return query, nil
+}
+
+// GetParametersJSON converts the parameters from GetParameters into the JSON format
+func (g *GetCurrenciesRequest) GetParametersJSON() ([]byte, error) {
+	params, err := g.GetParameters()
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(params)
+}
+
+// GetSlugParameters builds and checks the slug parameters and return the result in a map object
+func (g *GetCurrenciesRequest) GetSlugParameters() (map[string]interface{}, error) {
+	var params = map[string]interface{}{}
+
+	return params, nil
+}
+
+var GetCurrenciesRequestSlugReCache sync.Map
+
+func (g *GetCurrenciesRequest) applySlugsToUrl(url string, slugs map[string]string) string {
+	for _k, _v := range slugs {
+		var needleRE *regexp.Regexp
+
+		if cached, ok := GetCurrenciesRequestSlugReCache.Load(_k); ok {
+			needleRE = cached.(*regexp.Regexp)
+		} else {
+			needleRE = regexp.MustCompile(":" + _k + "\\b")
+			GetCurrenciesRequestSlugReCache.Store(_k, needleRE)
+		}
+
+		url = needleRE.ReplaceAllString(url, _v)
+	}
+
+	return url
+}
+
+func (g *GetCurrenciesRequest) iterateSlice(slice interface{}, _f func(it interface{})) {
+	sliceValue := reflect.ValueOf(slice)
+	for _i := 0; _i < sliceValue.Len(); _i++ {
+		it := sliceValue.Index(_i).Interface()
+		_f(it)
+	}
+}
+
+func (g *GetCurrenciesRequest) isVarSlice(_v interface{}) bool {
+	rt := reflect.TypeOf(_v)
+	switch rt.Kind() {
+	case reflect.Slice:
+		return true
+	}
+	return false
+}
+
+func (g *GetCurrenciesRequest) GetSlugsMap() (map[string]string, error) {
+	slugs := map[string]string{}
+	params, err := g.GetSlugParameters()
+	if err != nil {
+		return slugs, nil
+	}
+
+	for _k, _v := range params {
+		slugs[_k] = fmt.Sprintf("%v", _v)
+	}
+
+	return slugs, nil
+}
+
+// GetPath returns the request path of the API
+func (g *GetCurrenciesRequest) GetPath() string {
+	return "/v1/currencies"
+}
+
+// Do generates the request object and send the request object to the API endpoint
+func (g *GetCurrenciesRequest) Do(ctx context.Context) (_ *Response, err error) {
+	meta := &requestgen.RequestMeta{
+		Name:         "GetCurrenciesRequest",
+		Method:       "GET",
+		PathTemplate: g.GetPath(),
+		CacheTTL:     300000000000, // 5m0s
+	}
+	ctx = requestgen.WithRequestMeta(ctx, meta)
+
+	ctx, span := requestgen.StartRequestSpan(ctx, meta)
+	defer func() {
+		requestgen.EndSpan(span, err)
+	}()
+
+	// no body params
+	var params interface{}
+	query := url.Values{}
+
+	var apiURL string
+
+	apiURL = g.GetPath()
+
+	req, err := g.client.NewRequest(ctx, "GET", apiURL, query, params)
+	if err != nil {
+		return nil, err
+	}
+
+	span.AddEvent("build_request")
+
+	response, err := g.client.SendRequest(req)
+	if response != nil && response.Response != nil {
+		span.SetAttributes(requestgen.Attr(requestgen.AttrStatusCode, response.StatusCode))
+	}
+
+	span.AddEvent("send_request")
+	if err != nil {
+		return nil, err
+	}
+
+	var apiResponse Response
+
+	type responseUnmarshaler interface {
+		Unmarshal(data []byte) error
+	}
+
+	if unmarshaler, ok := interface{}(&apiResponse).(responseUnmarshaler); ok {
+		if err := unmarshaler.Unmarshal(response.Body); err != nil {
+			return nil, err
+		}
+	} else {
+		// The decoder is chosen by the content type of the response, JSON is used if the content type is unknown,
+		// since some API servers might not send the correct content type header.
+		if err := response.Decode(&apiResponse); err != nil {
+			return nil, err
+		}
+	}
+
+	span.AddEvent("decode_response")
+
+	type responseValidator interface {
+		Validate() error
+	}
+
+	if validator, ok := interface{}(&apiResponse).(responseValidator); ok {
+		if err := validator.Validate(); err != nil {
+			return nil, err
+		}
+	}
+	return &apiResponse, nil
+}
@@ -0,0 +1,34 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/requestgen"
+	"github.com/c9s/requestgen/mocktest"
+)
+
+func TestGetCurrenciesRequest_CacheTTL(t *testing.T) {
+	transport := mocktest.NewTransport(t)
+	route := transport.GET("/v1/currencies").RespondJSON(http.StatusOK, map[string]interface{}{
+		"code": "200000",
+		"data": []string{"BTC", "USDT"},
+	})
+
+	client := NewClient()
+	client.HttpClient.Transport = transport
+	client.ResponseCache = requestgen.NewLRUCache(0)
+
+	for i := 0; i < 3; i++ {
+		req := &GetCurrenciesRequest{client: client}
+		resp, err := req.Do(context.Background())
+		if assert.NoError(t, err) {
+			assert.Equal(t, "200000", resp.Code)
+		}
+	}
+
+	assert.Equal(t, 1, route.Calls())
+}
@@ -0,0 +1,29 @@
+package api
+
+import "github.com/c9s/requestgen"
+
+type OrderBook struct {
+	Sequence string      `json:"sequence"`
+	Bids     [][2]string `json:"bids"`
+	Asks     [][2]string `json:"asks"`
+}
+
+//go:generate go run ../../cmd/requestgen -type GetOrderBookRequest -url /v1/market/orderbook -method GET -responseType .Response -responseDataField Data -responseDataType .OrderBook -rateLimiter 100+20/1s -clientRateLimiter
+type GetOrderBookRequest struct {
+	client requestgen.APIClient
+
+	symbol string `param:"symbol,query,required"`
+	limit  *int   `param:"limit,query"`
+}
+
+// GetRequestWeight returns the rate limiter weight of the request, which grows with the depth limit
+func (r *GetOrderBookRequest) GetRequestWeight() int {
+	switch {
+	case r.limit == nil || *r.limit <= 100:
+		return 5
+	case *r.limit <= 500:
+		return 25
+	default:
+		return 50
+	}
+}
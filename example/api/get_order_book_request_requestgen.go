@@ -0,0 +1,267 @@
+// Code generated by "requestgen -type GetOrderBookRequest -url /v1/market/orderbook -method GET -responseType .Response -responseDataField Data -responseDataType .OrderBook -rateLimiter 100+20/1s -clientRateLimiter"; DO NOT EDIT.
+
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/c9s/requestgen"
+	"golang.org/x/time/rate"
+	"net/url"
+	"reflect"
+	"regexp"
+	"sync"
+	"time"
+)
+
+var GetOrderBookRequestLimiter = rate.NewLimiter(20, 100)
+
+/*
+ * Symbol sets
+ */
+func (r *GetOrderBookRequest) Symbol(symbol string) *GetOrderBookRequest {
+	r.symbol = symbol
+	return r
+}
+
+/*
+ * Limit sets
+ */
+func (r *GetOrderBookRequest) Limit(limit int) *GetOrderBookRequest {
+	r.limit = &limit
+	return r
+}
+
+// GetQueryParameters builds and checks the query parameters and returns url.Values
+func (r *GetOrderBookRequest) GetQueryParameters() (url.Values, error) {
+	var params = map[string]interface{}{}
+	// check symbol field -> json key symbol
+	symbol := r.symbol
+
+	// TEMPLATE check-required
+	if len(symbol) == 0 {
+		return nil, requestgen.NewValidationError("symbol", "symbol is required, empty string given")
+	}
+	// END TEMPLATE check-required
+
+	// assign parameter of symbol
+	params["symbol"] = symbol
+	// check limit field -> json key limit
+	if r.limit != nil {
+		limit := *r.limit
+
+		// TEMPLATE check-required
+
+		if limit == 0 {
+		}
+		// END TEMPLATE check-required
+
+		// assign parameter of limit
+		params["limit"] = limit
+	} else {
+	}
+
+	query := url.Values{}
+	for _k, _v := range params {
+		if r.isVarSlice(_v) {
+			r.iterateSlice(_v, func(it interface{}) {
+				query.Add(_k+"[]", fmt.Sprintf("%v", it))
+			})
+		} else {
+			query.Add(_k, fmt.Sprintf("%v", _v))
+		}
+	}
+
+	return query, nil
+}
+
+// GetParameters builds and checks the parameters and return the result in a map object
+func (r *GetOrderBookRequest) GetParameters() (map[string]interface{}, error) {
+	var params = map[string]interface{}{}
+
+	return params, nil
+}
+
+// GetParametersQuery converts the parameters from GetParameters into the url.Values format
+func (r *GetOrderBookRequest) GetParametersQuery() (url.Values, error) {
+	query := url.Values{}
+
+	params, err := r.GetParameters()
+	if err != nil {
+		return query, err
+	}
+
+	for _k, _v := range params {
+		if r.isVarSlice(_v) {
+			r.iterateSlice(_v, func(it interface{}) {
+				query.Add(_k+"[]", fmt.Sprintf("%v", it))
+			})
+		} else {
+			query.Add(_k, fmt.Sprintf("%v", _v))
+		}
+	}
+
+	return query, nil
+}
+
+// GetParametersJSON converts the parameters from GetParameters into the JSON format
+func (r *GetOrderBookRequest) GetParametersJSON() ([]byte, error) {
+	params, err := r.GetParameters()
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(params)
+}
+
+// GetSlugParameters builds and checks the slug parameters and return the result in a map object
+func (r *GetOrderBookRequest) GetSlugParameters() (map[string]interface{}, error) {
+	var params = map[string]interface{}{}
+
+	return params, nil
+}
+
+var GetOrderBookRequestSlugReCache sync.Map
+
+func (r *GetOrderBookRequest) applySlugsToUrl(url string, slugs map[string]string) string {
+	for _k, _v := range slugs {
+		var needleRE *regexp.Regexp
+
+		if cached, ok := GetOrderBookRequestSlugReCache.Load(_k); ok {
+			needleRE = cached.(*regexp.Regexp)
+		} else {
+			needleRE = regexp.MustCompile(":" + _k + "\\b")
+			GetOrderBookRequestSlugReCache.Store(_k, needleRE)
+		}
+
+		url = needleRE.ReplaceAllString(url, _v)
+	}
+
+	return url
+}
+
+func (r *GetOrderBookRequest) iterateSlice(slice interface{}, _f func(it interface{})) {
+	sliceValue := reflect.ValueOf(slice)
+	for _i := 0; _i < sliceValue.Len(); _i++ {
+		it := sliceValue.Index(_i).Interface()
+		_f(it)
+	}
+}
+
+func (r *GetOrderBookRequest) isVarSlice(_v interface{}) bool {
+	rt := reflect.TypeOf(_v)
+	switch rt.Kind() {
+	case reflect.Slice:
+		return true
+	}
+	return false
+}
+
+func (r *GetOrderBookRequest) GetSlugsMap() (map[string]string, error) {
+	slugs := map[string]string{}
+	params, err := r.GetSlugParameters()
+	if err != nil {
+		return slugs, nil
+	}
+
+	for _k, _v := range params {
+		slugs[_k] = fmt.Sprintf("%v", _v)
+	}
+
+	return slugs, nil
+}
+
+// GetPath returns the request path of the API
+func (r *GetOrderBookRequest) GetPath() string {
+	return "/v1/market/orderbook"
+}
+
+// Do generates the request object and send the request object to the API endpoint
+func (r *GetOrderBookRequest) Do(ctx context.Context) (_ *OrderBook, err error) {
+	meta := &requestgen.RequestMeta{
+		Name:         "GetOrderBookRequest",
+		Method:       "GET",
+		PathTemplate: r.GetPath(),
+		Weight:       r.GetRequestWeight(),
+	}
+	ctx = requestgen.WithRequestMeta(ctx, meta)
+
+	ctx, span := requestgen.StartRequestSpan(ctx, meta)
+	defer func() {
+		requestgen.EndSpan(span, err)
+	}()
+
+	rateLimiter := requestgen.ClientRateLimiter(r.client, "GetOrderBookRequest", GetOrderBookRequestLimiter)
+
+	waitStart := time.Now()
+	if err := rateLimiter.WaitN(ctx, meta.Weight); err != nil {
+		requestgen.ObserveRateLimitWait(r.client, meta, time.Since(waitStart), err)
+		return nil, err
+	}
+	requestgen.ObserveRateLimitWait(r.client, meta, time.Since(waitStart), nil)
+	span.AddEvent("rate_limit_wait", requestgen.Attr(requestgen.AttrRateLimitWait, meta.RateLimitWait))
+
+	// no body params
+	var params interface{}
+	query, err := r.GetQueryParameters()
+	if err != nil {
+		return nil, err
+	}
+
+	var apiURL string
+
+	apiURL = r.GetPath()
+
+	req, err := r.client.NewRequest(ctx, "GET", apiURL, query, params)
+	if err != nil {
+		return nil, err
+	}
+
+	span.AddEvent("build_request")
+
+	response, err := r.client.SendRequest(req)
+	if response != nil && response.Response != nil {
+		span.SetAttributes(requestgen.Attr(requestgen.AttrStatusCode, response.StatusCode))
+	}
+
+	span.AddEvent("send_request")
+	if err != nil {
+		return nil, err
+	}
+
+	var apiResponse Response
+
+	type responseUnmarshaler interface {
+		Unmarshal(data []byte) error
+	}
+
+	if unmarshaler, ok := interface{}(&apiResponse).(responseUnmarshaler); ok {
+		if err := unmarshaler.Unmarshal(response.Body); err != nil {
+			return nil, err
+		}
+	} else {
+		// The decoder is chosen by the content type of the response, JSON is used if the content type is unknown,
+		// since some API servers might not send the correct content type header.
+		if err := response.Decode(&apiResponse); err != nil {
+			return nil, err
+		}
+	}
+
+	span.AddEvent("decode_response")
+
+	type responseValidator interface {
+		Validate() error
+	}
+
+	if validator, ok := interface{}(&apiResponse).(responseValidator); ok {
+		if err := validator.Validate(); err != nil {
+			return nil, err
+		}
+	}
+	var data OrderBook
+	if err := json.Unmarshal(apiResponse.Data, &data); err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
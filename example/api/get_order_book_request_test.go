@@ -0,0 +1,62 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/requestgen"
+	"github.com/c9s/requestgen/mocktest"
+)
+
+func TestGetOrderBookRequest_Weight(t *testing.T) {
+	transport := mocktest.NewTransport(t)
+	transport.GET("/v1/market/orderbook").
+		WithQuery("limit", "1000").
+		RespondString(http.StatusOK, `{"code":"200000","data":{"sequence":"1","bids":[["100.1","0.1"]],"asks":[]}}`)
+
+	client := NewClient()
+	client.HttpClient.Transport = transport
+
+	req := &GetOrderBookRequest{client: client}
+	req.Symbol("BTC-USDT").Limit(1000)
+	assert.Equal(t, 50, req.GetRequestWeight())
+
+	before := GetOrderBookRequestLimiter.Tokens()
+	orderBook, err := req.Do(context.Background())
+	if assert.NoError(t, err) {
+		assert.Equal(t, "1", orderBook.Sequence)
+	}
+
+	// the request takes its weight from the limiter
+	assert.InDelta(t, before-50, GetOrderBookRequestLimiter.Tokens(), 1)
+}
+
+func TestGetOrderBookRequest_ClientRateLimiter(t *testing.T) {
+	transport := mocktest.NewTransport(t)
+	transport.GET("/v1/market/orderbook").
+		RespondString(http.StatusOK, `{"code":"200000","data":{"sequence":"1","bids":[],"asks":[]}}`)
+
+	// each account has its own budget
+	newClient := func() *RestClient {
+		client := NewClient()
+		client.HttpClient.Transport = transport
+		client.RateLimiters = requestgen.NewRateLimiterRegistry()
+		return client
+	}
+
+	accountA, accountB := newClient(), newClient()
+
+	req := &GetOrderBookRequest{client: accountA}
+	_, err := req.Symbol("BTC-USDT").Limit(1000).Do(context.Background())
+	assert.NoError(t, err)
+
+	limiterA := accountA.RateLimiters.Get("GetOrderBookRequest", nil)
+	limiterB := accountB.RateLimiters.Get("GetOrderBookRequest", GetOrderBookRequestLimiter)
+	if assert.NotNil(t, limiterA) {
+		assert.InDelta(t, 50, limiterA.Tokens(), 1)
+	}
+	assert.InDelta(t, 100, limiterB.Tokens(), 1)
+}
@@ -0,0 +1,27 @@
+package api
+
+import "github.com/c9s/requestgen"
+
+type ReportRow struct {
+	Date   string  `xml:"date" csv:"date"`
+	Symbol string  `xml:"symbol" csv:"symbol"`
+	Volume float64 `xml:"volume" csv:"volume"`
+}
+
+type Report struct {
+	Rows []ReportRow `xml:"row"`
+}
+
+//go:generate go run ../../cmd/requestgen -type GetReportRequest -url /v1/reports/:reportID -method GET -responseType .Report -responseDecoder xml
+type GetReportRequest struct {
+	client requestgen.APIClient
+
+	reportID string `param:"reportID,slug,required"`
+}
+
+//go:generate go run ../../cmd/requestgen -type GetReportCSVRequest -url /v1/reports/:reportID/csv -method GET -responseType []ReportRow
+type GetReportCSVRequest struct {
+	client requestgen.APIClient
+
+	reportID string `param:"reportID,slug,required"`
+}
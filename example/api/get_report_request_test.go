@@ -0,0 +1,84 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/requestgen"
+	"github.com/c9s/requestgen/mocktest"
+)
+
+func TestGetReportRequest_XML(t *testing.T) {
+	transport := mocktest.NewTransport(t)
+
+	// the content type is missing, the decoder is forced by the -responseDecoder flag
+	transport.GET("/v1/reports/:reportID").RespondString(http.StatusOK, `<report>
+		<row><date>2024-01-01</date><symbol>BTC-USDT</symbol><volume>10.5</volume></row>
+		<row><date>2024-01-02</date><symbol>BTC-USDT</symbol><volume>11</volume></row>
+	</report>`)
+
+	client := NewClient()
+	client.HttpClient.Transport = transport
+
+	req := &GetReportRequest{client: client}
+	report, err := req.ReportID("daily").Do(context.Background())
+	if assert.NoError(t, err) && assert.Len(t, report.Rows, 2) {
+		assert.Equal(t, "2024-01-01", report.Rows[0].Date)
+		assert.Equal(t, 11.0, report.Rows[1].Volume)
+	}
+}
+
+func TestGetReportCSVRequest_CSV(t *testing.T) {
+	transport := mocktest.NewTransport(t)
+	transport.GET("/v1/reports/:reportID/csv").RespondWith(func(req *http.Request) (*http.Response, error) {
+		resp := mocktest.BuildResponseString(http.StatusOK, "date,symbol,volume\n2024-01-01,BTC-USDT,10.5\n")
+		resp.Header.Set("Content-Type", "text/csv; charset=utf-8")
+		return resp, nil
+	})
+
+	client := NewClient()
+	client.HttpClient.Transport = transport
+
+	req := &GetReportCSVRequest{client: client}
+	rows, err := req.ReportID("daily").Do(context.Background())
+	if assert.NoError(t, err) && assert.Len(t, rows, 1) {
+		assert.Equal(t, ReportRow{Date: "2024-01-01", Symbol: "BTC-USDT", Volume: 10.5}, rows[0])
+	}
+}
+
+type recordingObserver struct {
+	observations []*requestgen.Observation
+}
+
+func (o *recordingObserver) ObserveRateLimitWait(meta *requestgen.RequestMeta, wait time.Duration, err error) {
+}
+
+func (o *recordingObserver) ObserveRequest(observation *requestgen.Observation) {
+	o.observations = append(o.observations, observation)
+}
+
+func TestGetReportRequest_Observer(t *testing.T) {
+	transport := mocktest.NewTransport(t)
+	transport.GET("/v1/reports/:reportID").RespondString(http.StatusOK, `<report></report>`)
+
+	observer := &recordingObserver{}
+	client := NewClient()
+	client.HttpClient.Transport = transport
+	client.Observer = observer
+
+	req := &GetReportRequest{client: client}
+	_, err := req.ReportID("daily").Do(context.Background())
+	assert.NoError(t, err)
+
+	if assert.Len(t, observer.observations, 1) {
+		// the path template is reported instead of the slug-substituted path
+		assert.Equal(t, "GetReportRequest", observer.observations[0].Name)
+		assert.Equal(t, "GET", observer.observations[0].Method)
+		assert.Equal(t, "/v1/reports/:reportID", observer.observations[0].PathTemplate)
+		assert.Equal(t, http.StatusOK, observer.observations[0].StatusCode)
+	}
+}
@@ -0,0 +1,10 @@
+package api
+
+import "github.com/c9s/requestgen"
+
+//go:generate go run ../../cmd/requestgen -type GetTickerRequest -url /v1/market/orderbook/level1 -method GET -responseType .Response -errorType .APIError -coalesce
+type GetTickerRequest struct {
+	client requestgen.APIClient
+
+	symbol string `param:"symbol,query,required"`
+}
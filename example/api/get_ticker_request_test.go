@@ -0,0 +1,204 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/requestgen"
+	"github.com/c9s/requestgen/mocktest"
+	"github.com/c9s/requestgen/replay"
+)
+
+func TestGetTickerRequest_APIError(t *testing.T) {
+	transport := mocktest.NewTransport(t)
+	transport.GET("/v1/market/orderbook/level1").RespondJSON(http.StatusBadRequest, map[string]interface{}{
+		"code": "400100",
+		"msg":  "symbol is invalid",
+	})
+
+	client := NewClient()
+	client.HttpClient.Transport = transport
+
+	req := &GetTickerRequest{client: client}
+	_, err := req.Symbol("BTC-USDT").Do(context.Background())
+
+	var apiErr *APIError
+	if assert.True(t, errors.As(err, &apiErr)) {
+		assert.Equal(t, "400100", apiErr.Code)
+		assert.Equal(t, "symbol is invalid", apiErr.Message)
+	}
+}
+
+func TestGetTickerRequest_APIErrorInSuccessfulResponse(t *testing.T) {
+	transport := mocktest.NewTransport(t)
+	transport.GET("/v1/market/orderbook/level1").RespondJSON(http.StatusOK, map[string]interface{}{
+		"code": "429000",
+		"msg":  "too many requests",
+	})
+
+	client := NewClient()
+	client.HttpClient.Transport = transport
+
+	req := &GetTickerRequest{client: client}
+	_, err := req.Symbol("BTC-USDT").Do(context.Background())
+
+	var apiErr *APIError
+	if assert.True(t, errors.As(err, &apiErr)) {
+		assert.Equal(t, "429000", apiErr.Code)
+	}
+}
+
+func TestGetTickerRequest_Do(t *testing.T) {
+	transport := mocktest.NewTransport(t)
+	route := transport.GET("/v1/market/orderbook/level1").
+		WithQuery("symbol", "BTC-USDT").
+		RespondJSON(http.StatusOK, map[string]interface{}{
+			"code": "200000",
+			"data": map[string]interface{}{"price": "100.0"},
+		})
+
+	client := NewClient()
+	client.HttpClient.Transport = transport
+
+	req := &GetTickerRequest{client: client}
+	resp, err := req.Symbol("BTC-USDT").Do(context.Background())
+	if assert.NoError(t, err) {
+		assert.Equal(t, "200000", resp.Code)
+	}
+	assert.Equal(t, 1, route.Calls())
+}
+
+func TestGetTickerRequest_ValidationError(t *testing.T) {
+	req := &GetTickerRequest{client: NewClient()}
+	_, err := req.Do(context.Background())
+	assert.True(t, requestgen.IsValidationError(err))
+	assert.False(t, requestgen.IsRetryable(err))
+}
+
+func TestGetTickerRequest_Replay(t *testing.T) {
+	// the cassette can be re-recorded against the real API server with replay.ModeRecord and transport.Save()
+	transport, err := replay.NewTransport("testdata/get_ticker.json", replay.ModeReplay, "KC-API-KEY", "KC-API-SIGN")
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	client := NewClient()
+	client.HttpClient.Transport = transport
+
+	req := &GetTickerRequest{client: client}
+	resp, err := req.Symbol("BTC-USDT").Do(context.Background())
+	if assert.NoError(t, err) {
+		assert.Equal(t, "200000", resp.Code)
+		assert.JSONEq(t, `{"time":1700000000000,"sequence":"1550467636704","price":"37000.1","size":"0.01","bestBid":"37000","bestBidSize":"0.5","bestAsk":"37000.1","bestAskSize":"0.2"}`, string(resp.Data))
+	}
+}
+
+type recordingSpan struct {
+	name       string
+	attributes map[string]interface{}
+	events     []string
+	ended      bool
+}
+
+func (s *recordingSpan) SetAttributes(attributes ...requestgen.Attribute) {
+	for _, attr := range attributes {
+		s.attributes[attr.Key] = attr.Value
+	}
+}
+
+func (s *recordingSpan) AddEvent(name string, attributes ...requestgen.Attribute) {
+	s.events = append(s.events, name)
+}
+
+func (s *recordingSpan) RecordError(err error) {}
+
+func (s *recordingSpan) End() {
+	s.ended = true
+}
+
+func TestGetTickerRequest_Tracing(t *testing.T) {
+	var spans []*recordingSpan
+	requestgen.SetTracer(requestgen.TracerFunc(func(ctx context.Context, name string) (context.Context, requestgen.Span) {
+		span := &recordingSpan{name: name, attributes: map[string]interface{}{}}
+		spans = append(spans, span)
+		return ctx, span
+	}))
+	t.Cleanup(func() { requestgen.SetTracer(nil) })
+
+	transport := mocktest.NewTransport(t)
+	transport.GET("/v1/market/orderbook/level1").RespondJSON(http.StatusOK, map[string]interface{}{"code": "200000"})
+
+	client := NewClient()
+	client.HttpClient.Transport = transport
+
+	req := &GetTickerRequest{client: client}
+	_, err := req.Symbol("BTC-USDT").Do(context.Background())
+	assert.NoError(t, err)
+
+	if assert.Len(t, spans, 1) {
+		assert.Equal(t, "GetTickerRequest", spans[0].name)
+		assert.Equal(t, map[string]interface{}{
+			requestgen.AttrRequestType: "GetTickerRequest",
+			requestgen.AttrHTTPMethod:  "GET",
+			requestgen.AttrHTTPRoute:   "/v1/market/orderbook/level1",
+			requestgen.AttrStatusCode:  http.StatusOK,
+		}, spans[0].attributes)
+		assert.Equal(t, []string{"build_request", "send_request", "decode_response"}, spans[0].events)
+		assert.True(t, spans[0].ended)
+	}
+}
+
+func TestGetTickerRequest_CircuitOpen(t *testing.T) {
+	transport := mocktest.NewTransport(t)
+	route := transport.GET("/v1/market/orderbook/level1").RespondStatus(http.StatusServiceUnavailable)
+
+	client := NewClient()
+	client.HttpClient.Transport = transport
+	client.CircuitBreaker = requestgen.NewCircuitBreaker(2, time.Minute)
+
+	req := &GetTickerRequest{client: client}
+	for i := 0; i < 2; i++ {
+		_, err := req.Symbol("BTC-USDT").Do(context.Background())
+		assert.Equal(t, http.StatusServiceUnavailable, requestgen.StatusCode(err))
+	}
+
+	_, err := req.Symbol("BTC-USDT").Do(context.Background())
+	var circuitErr *requestgen.ErrCircuitOpen
+	if assert.True(t, errors.As(err, &circuitErr)) {
+		assert.Equal(t, "GET /v1/market/orderbook/level1", circuitErr.Key)
+	}
+	assert.Equal(t, 2, route.Calls())
+}
+
+func TestGetTickerRequest_Coalesce(t *testing.T) {
+	transport := mocktest.NewTransport(t)
+	route := transport.GET("/v1/market/orderbook/level1").RespondWith(func(req *http.Request) (*http.Response, error) {
+		time.Sleep(100 * time.Millisecond)
+		return mocktest.BuildResponseJson(http.StatusOK, map[string]interface{}{"code": "200000"}), nil
+	})
+
+	client := NewClient()
+	client.HttpClient.Transport = transport
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			req := &GetTickerRequest{client: client}
+			resp, err := req.Symbol("BTC-USDT").Do(context.Background())
+			if assert.NoError(t, err) {
+				assert.Equal(t, "200000", resp.Code)
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, 1, route.Calls())
+}
@@ -0,0 +1,18 @@
+package api
+
+import "github.com/c9s/requestgen"
+
+type Trade struct {
+	Sequence string `json:"sequence"`
+	Price    string `json:"price"`
+	Size     string `json:"size"`
+	Side     string `json:"side"`
+	Time     int64  `json:"time"`
+}
+
+//go:generate go run ../../cmd/requestgen -type GetTradeHistoriesRequest -url /v1/market/histories -method GET -responseType .Response -responseDataField Data -responseDataType []Trade -stream -maxResponseBytes 10485760 -adaptiveRateLimit
+type GetTradeHistoriesRequest struct {
+	client requestgen.APIClient
+
+	symbol string `param:"symbol,query,required"`
+}
@@ -0,0 +1,68 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/requestgen"
+	"github.com/c9s/requestgen/mocktest"
+)
+
+func TestGetTradeHistoriesRequest_DoStream(t *testing.T) {
+	transport := mocktest.NewTransport(t)
+	transport.GET("/v1/market/histories").
+		WithQuery("symbol", "BTC-USDT").
+		RespondString(http.StatusOK, `{"code":"200000","data":[
+			{"sequence":"1","price":"100.1","size":"0.1","side":"buy","time":1},
+			{"sequence":"2","price":"100.2","size":"0.2","side":"sell","time":2}
+		]}`)
+
+	client := NewClient()
+	client.HttpClient.Transport = transport
+
+	req := &GetTradeHistoriesRequest{client: client}
+	resp, err := req.Symbol("BTC-USDT").DoStream(context.Background())
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer resp.Close()
+
+	var sequences []string
+	for trade, err := range requestgen.DecodeJSONArray[Trade](resp, "data") {
+		if !assert.NoError(t, err) {
+			return
+		}
+		sequences = append(sequences, trade.Sequence)
+	}
+
+	assert.Equal(t, []string{"1", "2"}, sequences)
+}
+
+func TestGetTradeHistoriesRequest_AdaptiveRateLimit(t *testing.T) {
+	transport := mocktest.NewTransport(t)
+	route := transport.GET("/v1/market/histories").RespondWith(func(req *http.Request) (*http.Response, error) {
+		resp := mocktest.BuildResponseString(http.StatusOK, `{"code":"200000","data":[]}`)
+		resp.Header.Set("X-RateLimit-Remaining", "0")
+		resp.Header.Set("X-RateLimit-Reset", "0.2")
+		return resp, nil
+	})
+
+	client := NewClient()
+	client.HttpClient.Transport = transport
+	client.AdaptiveRateLimiter = requestgen.NewAdaptiveRateLimiter("X-RateLimit-Remaining", "X-RateLimit-Reset", 0)
+
+	start := time.Now()
+	for i := 0; i < 2; i++ {
+		req := &GetTradeHistoriesRequest{client: client}
+		_, err := req.Symbol("BTC-USDT").Do(context.Background())
+		assert.NoError(t, err)
+	}
+
+	// the second request waits for the budget reset
+	assert.True(t, time.Since(start) > 150*time.Millisecond)
+	assert.Equal(t, 2, route.Calls())
+}
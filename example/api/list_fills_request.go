@@ -0,0 +1,23 @@
+package api
+
+import "github.com/c9s/requestgen"
+
+type Fill struct {
+	TradeId string `json:"tradeId"`
+	OrderId string `json:"orderId"`
+	Price   string `json:"price"`
+	Size    string `json:"size"`
+}
+
+type FillPage struct {
+	Items      []Fill `json:"items"`
+	NextCursor string `json:"nextCursor"`
+}
+
+//go:generate go run ../../cmd/requestgen -type ListFillsRequest -url /v1/fills -method GET -responseType .Response -responseDataField Data -responseDataType .FillPage -paginate cursor:after,next:NextCursor,items:Items
+type ListFillsRequest struct {
+	client requestgen.APIClient
+
+	symbol string  `param:"symbol,query,required"`
+	after  *string `param:"after,query"`
+}
@@ -0,0 +1,303 @@
+// Code generated by "requestgen -type ListFillsRequest -url /v1/fills -method GET -responseType .Response -responseDataField Data -responseDataType .FillPage -paginate cursor:after,next:NextCursor,items:Items"; DO NOT EDIT.
+
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/c9s/requestgen"
+	"iter"
+	"net/url"
+	"reflect"
+	"regexp"
+	"sync"
+)
+
+/*
+ * Symbol sets
+ */
+func (l *ListFillsRequest) Symbol(symbol string) *ListFillsRequest {
+	l.symbol = symbol
+	return l
+}
+
+/*
+ * After sets
+ */
+func (l *ListFillsRequest) After(after string) *ListFillsRequest {
+	l.after = &after
+	return l
+}
+
+// GetQueryParameters builds and checks the query parameters and returns url.Values
+func (l *ListFillsRequest) GetQueryParameters() (url.Values, error) {
+	var params = map[string]interface{}{}
+	// check symbol field -> json key symbol
+	symbol := l.symbol
+
+	// TEMPLATE check-required
+	if len(symbol) == 0 {
+		return nil, requestgen.NewValidationError("symbol", "symbol is required, empty string given")
+	}
+	// END TEMPLATE check-required
+
+	// assign parameter of symbol
+	params["symbol"] = symbol
+	// check after field -> json key after
+	if l.after != nil {
+		after := *l.after
+
+		// TEMPLATE check-required
+		if len(after) == 0 {
+		}
+		// END TEMPLATE check-required
+
+		// assign parameter of after
+		params["after"] = after
+	} else {
+	}
+
+	query := url.Values{}
+	for _k, _v := range params {
+		if l.isVarSlice(_v) {
+			l.iterateSlice(_v, func(it interface{}) {
+				query.Add(_k+"[]", fmt.Sprintf("%v", it))
+			})
+		} else {
+			query.Add(_k, fmt.Sprintf("%v", _v))
+		}
+	}
+
+	return query, nil
+}
+
+// GetParameters builds and checks the parameters and return the result in a map object
+func (l *ListFillsRequest) GetParameters() (map[string]interface{}, error) {
+	var params = map[string]interface{}{}
+
+	return params, nil
+}
+
+// GetParametersQuery converts the parameters from GetParameters into the url.Values format
+func (l *ListFillsRequest) GetParametersQuery() (url.Values, error) {
+	query := url.Values{}
+
+	params, err := l.GetParameters()
+	if err != nil {
+		return query, err
+	}
+
+	for _k, _v := range params {
+		if l.isVarSlice(_v) {
+			l.iterateSlice(_v, func(it interface{}) {
+				query.Add(_k+"[]", fmt.Sprintf("%v", it))
+			})
+		} else {
+			query.Add(_k, fmt.Sprintf("%v", _v))
+		}
+	}
+
+	return query, nil
+}
+
+// GetParametersJSON converts the parameters from GetParameters into the JSON format
+func (l *ListFillsRequest) GetParametersJSON() ([]byte, error) {
+	params, err := l.GetParameters()
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(params)
+}
+
+// GetSlugParameters builds and checks the slug parameters and return the result in a map object
+func (l *ListFillsRequest) GetSlugParameters() (map[string]interface{}, error) {
+	var params = map[string]interface{}{}
+
+	return params, nil
+}
+
+var ListFillsRequestSlugReCache sync.Map
+
+func (l *ListFillsRequest) applySlugsToUrl(url string, slugs map[string]string) string {
+	for _k, _v := range slugs {
+		var needleRE *regexp.Regexp
+
+		if cached, ok := ListFillsRequestSlugReCache.Load(_k); ok {
+			needleRE = cached.(*regexp.Regexp)
+		} else {
+			needleRE = regexp.MustCompile(":" + _k + "\\b")
+			ListFillsRequestSlugReCache.Store(_k, needleRE)
+		}
+
+		url = needleRE.ReplaceAllString(url, _v)
+	}
+
+	return url
+}
+
+func (l *ListFillsRequest) iterateSlice(slice interface{}, _f func(it interface{})) {
+	sliceValue := reflect.ValueOf(slice)
+	for _i := 0; _i < sliceValue.Len(); _i++ {
+		it := sliceValue.Index(_i).Interface()
+		_f(it)
+	}
+}
+
+func (l *ListFillsRequest) isVarSlice(_v interface{}) bool {
+	rt := reflect.TypeOf(_v)
+	switch rt.Kind() {
+	case reflect.Slice:
+		return true
+	}
+	return false
+}
+
+func (l *ListFillsRequest) GetSlugsMap() (map[string]string, error) {
+	slugs := map[string]string{}
+	params, err := l.GetSlugParameters()
+	if err != nil {
+		return slugs, nil
+	}
+
+	for _k, _v := range params {
+		slugs[_k] = fmt.Sprintf("%v", _v)
+	}
+
+	return slugs, nil
+}
+
+// GetPath returns the request path of the API
+func (l *ListFillsRequest) GetPath() string {
+	return "/v1/fills"
+}
+
+// Do generates the request object and send the request object to the API endpoint
+func (l *ListFillsRequest) Do(ctx context.Context) (_ *FillPage, err error) {
+	apiResponse, err := l.doResponse(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var data FillPage
+	if err := json.Unmarshal(apiResponse.Data, &data); err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+// doResponse sends the request and returns the decoded response
+func (l *ListFillsRequest) doResponse(ctx context.Context) (_ *Response, err error) {
+	meta := &requestgen.RequestMeta{
+		Name:         "ListFillsRequest",
+		Method:       "GET",
+		PathTemplate: l.GetPath(),
+	}
+	ctx = requestgen.WithRequestMeta(ctx, meta)
+
+	ctx, span := requestgen.StartRequestSpan(ctx, meta)
+	defer func() {
+		requestgen.EndSpan(span, err)
+	}()
+
+	// no body params
+	var params interface{}
+	query, err := l.GetQueryParameters()
+	if err != nil {
+		return nil, err
+	}
+
+	var apiURL string
+
+	apiURL = l.GetPath()
+
+	req, err := l.client.NewRequest(ctx, "GET", apiURL, query, params)
+	if err != nil {
+		return nil, err
+	}
+
+	span.AddEvent("build_request")
+
+	response, err := l.client.SendRequest(req)
+	if response != nil && response.Response != nil {
+		span.SetAttributes(requestgen.Attr(requestgen.AttrStatusCode, response.StatusCode))
+	}
+
+	span.AddEvent("send_request")
+	if err != nil {
+		return nil, err
+	}
+
+	var apiResponse Response
+
+	type responseUnmarshaler interface {
+		Unmarshal(data []byte) error
+	}
+
+	if unmarshaler, ok := interface{}(&apiResponse).(responseUnmarshaler); ok {
+		if err := unmarshaler.Unmarshal(response.Body); err != nil {
+			return nil, err
+		}
+	} else {
+		// The decoder is chosen by the content type of the response, JSON is used if the content type is unknown,
+		// since some API servers might not send the correct content type header.
+		if err := response.Decode(&apiResponse); err != nil {
+			return nil, err
+		}
+	}
+
+	span.AddEvent("decode_response")
+
+	type responseValidator interface {
+		Validate() error
+	}
+
+	if validator, ok := interface{}(&apiResponse).(responseValidator); ok {
+		if err := validator.Validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	return &apiResponse, nil
+}
+
+// Iterate sends the request page by page and yields the items until the last page or the first error.
+// The after field of the request is advanced by the iteration.
+func (l *ListFillsRequest) Iterate(ctx context.Context) iter.Seq2[Fill, error] {
+	return func(yield func(Fill, error) bool) {
+		var cursor string
+		if l.after != nil {
+			cursor = *l.after
+		}
+
+		for {
+			apiResponse, err := l.doResponse(ctx)
+			if err != nil {
+				var zero Fill
+				yield(zero, err)
+				return
+			}
+
+			var data FillPage
+			if err := json.Unmarshal(apiResponse.Data, &data); err != nil {
+				var zero Fill
+				yield(zero, err)
+				return
+			}
+
+			items := data.Items
+			for _, item := range items {
+				if !yield(item, nil) {
+					return
+				}
+			}
+			next := data.NextCursor
+			if len(items) == 0 || next == "" || next == cursor {
+				return
+			}
+
+			cursor = next
+			l.After(cursor)
+		}
+	}
+}
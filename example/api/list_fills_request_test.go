@@ -0,0 +1,43 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/requestgen/mocktest"
+)
+
+func TestListFillsRequest_Iterate(t *testing.T) {
+	transport := mocktest.NewTransport(t)
+	route := transport.GET("/v1/fills").WithQuery("symbol", "BTC-USDT").RespondWith(func(req *http.Request) (*http.Response, error) {
+		switch req.URL.Query().Get("after") {
+		case "":
+			return mocktest.BuildResponseString(http.StatusOK,
+				`{"code":"200000","data":{"items":[{"tradeId":"1"},{"tradeId":"2"}],"nextCursor":"c1"}}`), nil
+		case "c1":
+			return mocktest.BuildResponseString(http.StatusOK,
+				`{"code":"200000","data":{"items":[{"tradeId":"3"}],"nextCursor":""}}`), nil
+		}
+		return mocktest.BuildResponse(http.StatusBadRequest, nil), nil
+	})
+
+	client := NewClient()
+	client.HttpClient.Transport = transport
+
+	req := &ListFillsRequest{client: client}
+	req.Symbol("BTC-USDT")
+
+	var tradeIds []string
+	for fill, err := range req.Iterate(context.Background()) {
+		if !assert.NoError(t, err) {
+			break
+		}
+		tradeIds = append(tradeIds, fill.TradeId)
+	}
+
+	assert.Equal(t, []string{"1", "2", "3"}, tradeIds)
+	assert.Equal(t, 2, route.Calls())
+}
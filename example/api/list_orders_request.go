@@ -0,0 +1,12 @@
+package api
+
+import "github.com/c9s/requestgen"
+
+//go:generate go run ../../cmd/requestgen -type ListOrdersRequest -url /v1/orders -method GET -responseType .Response -responseDataField Data -responseDataType []Order -paginate page:currentPage,total:TotalPage
+type ListOrdersRequest struct {
+	client requestgen.APIClient
+
+	status      *string `param:"status,query"`
+	currentPage *int    `param:"currentPage,query"`
+	pageSize    *int    `param:"pageSize,query"`
+}
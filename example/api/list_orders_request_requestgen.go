@@ -0,0 +1,329 @@
+// Code generated by "requestgen -type ListOrdersRequest -url /v1/orders -method GET -responseType .Response -responseDataField Data -responseDataType []Order -paginate page:currentPage,total:TotalPage"; DO NOT EDIT.
+
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/c9s/requestgen"
+	"iter"
+	"net/url"
+	"reflect"
+	"regexp"
+	"sync"
+)
+
+/*
+ * Status sets
+ */
+func (l *ListOrdersRequest) Status(status string) *ListOrdersRequest {
+	l.status = &status
+	return l
+}
+
+/*
+ * CurrentPage sets
+ */
+func (l *ListOrdersRequest) CurrentPage(currentPage int) *ListOrdersRequest {
+	l.currentPage = &currentPage
+	return l
+}
+
+/*
+ * PageSize sets
+ */
+func (l *ListOrdersRequest) PageSize(pageSize int) *ListOrdersRequest {
+	l.pageSize = &pageSize
+	return l
+}
+
+// GetQueryParameters builds and checks the query parameters and returns url.Values
+func (l *ListOrdersRequest) GetQueryParameters() (url.Values, error) {
+	var params = map[string]interface{}{}
+	// check status field -> json key status
+	if l.status != nil {
+		status := *l.status
+
+		// TEMPLATE check-required
+		if len(status) == 0 {
+		}
+		// END TEMPLATE check-required
+
+		// assign parameter of status
+		params["status"] = status
+	} else {
+	}
+	// check currentPage field -> json key currentPage
+	if l.currentPage != nil {
+		currentPage := *l.currentPage
+
+		// TEMPLATE check-required
+
+		if currentPage == 0 {
+		}
+		// END TEMPLATE check-required
+
+		// assign parameter of currentPage
+		params["currentPage"] = currentPage
+	} else {
+	}
+	// check pageSize field -> json key pageSize
+	if l.pageSize != nil {
+		pageSize := *l.pageSize
+
+		// TEMPLATE check-required
+
+		if pageSize == 0 {
+		}
+		// END TEMPLATE check-required
+
+		// assign parameter of pageSize
+		params["pageSize"] = pageSize
+	} else {
+	}
+
+	query := url.Values{}
+	for _k, _v := range params {
+		if l.isVarSlice(_v) {
+			l.iterateSlice(_v, func(it interface{}) {
+				query.Add(_k+"[]", fmt.Sprintf("%v", it))
+			})
+		} else {
+			query.Add(_k, fmt.Sprintf("%v", _v))
+		}
+	}
+
+	return query, nil
+}
+
+// GetParameters builds and checks the parameters and return the result in a map object
+func (l *ListOrdersRequest) GetParameters() (map[string]interface{}, error) {
+	var params = map[string]interface{}{}
+
+	return params, nil
+}
+
+// GetParametersQuery converts the parameters from GetParameters into the url.Values format
+func (l *ListOrdersRequest) GetParametersQuery() (url.Values, error) {
+	query := url.Values{}
+
+	params, err := l.GetParameters()
+	if err != nil {
+		return query, err
+	}
+
+	for _k, _v := range params {
+		if l.isVarSlice(_v) {
+			l.iterateSlice(_v, func(it interface{}) {
+				query.Add(_k+"[]", fmt.Sprintf("%v", it))
+			})
+		} else {
+			query.Add(_k, fmt.Sprintf("%v", _v))
+		}
+	}
+
+	return query, nil
+}
+
+// GetParametersJSON converts the parameters from GetParameters into the JSON format
+func (l *ListOrdersRequest) GetParametersJSON() ([]byte, error) {
+	params, err := l.GetParameters()
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(params)
+}
+
+// GetSlugParameters builds and checks the slug parameters and return the result in a map object
+func (l *ListOrdersRequest) GetSlugParameters() (map[string]interface{}, error) {
+	var params = map[string]interface{}{}
+
+	return params, nil
+}
+
+var ListOrdersRequestSlugReCache sync.Map
+
+func (l *ListOrdersRequest) applySlugsToUrl(url string, slugs map[string]string) string {
+	for _k, _v := range slugs {
+		var needleRE *regexp.Regexp
+
+		if cached, ok := ListOrdersRequestSlugReCache.Load(_k); ok {
+			needleRE = cached.(*regexp.Regexp)
+		} else {
+			needleRE = regexp.MustCompile(":" + _k + "\\b")
+			ListOrdersRequestSlugReCache.Store(_k, needleRE)
+		}
+
+		url = needleRE.ReplaceAllString(url, _v)
+	}
+
+	return url
+}
+
+func (l *ListOrdersRequest) iterateSlice(slice interface{}, _f func(it interface{})) {
+	sliceValue := reflect.ValueOf(slice)
+	for _i := 0; _i < sliceValue.Len(); _i++ {
+		it := sliceValue.Index(_i).Interface()
+		_f(it)
+	}
+}
+
+func (l *ListOrdersRequest) isVarSlice(_v interface{}) bool {
+	rt := reflect.TypeOf(_v)
+	switch rt.Kind() {
+	case reflect.Slice:
+		return true
+	}
+	return false
+}
+
+func (l *ListOrdersRequest) GetSlugsMap() (map[string]string, error) {
+	slugs := map[string]string{}
+	params, err := l.GetSlugParameters()
+	if err != nil {
+		return slugs, nil
+	}
+
+	for _k, _v := range params {
+		slugs[_k] = fmt.Sprintf("%v", _v)
+	}
+
+	return slugs, nil
+}
+
+// GetPath returns the request path of the API
+func (l *ListOrdersRequest) GetPath() string {
+	return "/v1/orders"
+}
+
+// Do generates the request object and send the request object to the API endpoint
+func (l *ListOrdersRequest) Do(ctx context.Context) (_ []Order, err error) {
+	apiResponse, err := l.doResponse(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var data []Order
+	if err := json.Unmarshal(apiResponse.Data, &data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// doResponse sends the request and returns the decoded response
+func (l *ListOrdersRequest) doResponse(ctx context.Context) (_ *Response, err error) {
+	meta := &requestgen.RequestMeta{
+		Name:         "ListOrdersRequest",
+		Method:       "GET",
+		PathTemplate: l.GetPath(),
+	}
+	ctx = requestgen.WithRequestMeta(ctx, meta)
+
+	ctx, span := requestgen.StartRequestSpan(ctx, meta)
+	defer func() {
+		requestgen.EndSpan(span, err)
+	}()
+
+	// no body params
+	var params interface{}
+	query, err := l.GetQueryParameters()
+	if err != nil {
+		return nil, err
+	}
+
+	var apiURL string
+
+	apiURL = l.GetPath()
+
+	req, err := l.client.NewRequest(ctx, "GET", apiURL, query, params)
+	if err != nil {
+		return nil, err
+	}
+
+	span.AddEvent("build_request")
+
+	response, err := l.client.SendRequest(req)
+	if response != nil && response.Response != nil {
+		span.SetAttributes(requestgen.Attr(requestgen.AttrStatusCode, response.StatusCode))
+	}
+
+	span.AddEvent("send_request")
+	if err != nil {
+		return nil, err
+	}
+
+	var apiResponse Response
+
+	type responseUnmarshaler interface {
+		Unmarshal(data []byte) error
+	}
+
+	if unmarshaler, ok := interface{}(&apiResponse).(responseUnmarshaler); ok {
+		if err := unmarshaler.Unmarshal(response.Body); err != nil {
+			return nil, err
+		}
+	} else {
+		// The decoder is chosen by the content type of the response, JSON is used if the content type is unknown,
+		// since some API servers might not send the correct content type header.
+		if err := response.Decode(&apiResponse); err != nil {
+			return nil, err
+		}
+	}
+
+	span.AddEvent("decode_response")
+
+	type responseValidator interface {
+		Validate() error
+	}
+
+	if validator, ok := interface{}(&apiResponse).(responseValidator); ok {
+		if err := validator.Validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	return &apiResponse, nil
+}
+
+// Iterate sends the request page by page and yields the items until the last page or the first error.
+// The currentPage field of the request is advanced by the iteration.
+func (l *ListOrdersRequest) Iterate(ctx context.Context) iter.Seq2[Order, error] {
+	return func(yield func(Order, error) bool) {
+		page := 1
+		if l.currentPage != nil {
+			page = *l.currentPage
+		}
+
+		for {
+			l.CurrentPage(page)
+
+			apiResponse, err := l.doResponse(ctx)
+			if err != nil {
+				var zero Order
+				yield(zero, err)
+				return
+			}
+
+			var data []Order
+			if err := json.Unmarshal(apiResponse.Data, &data); err != nil {
+				var zero Order
+				yield(zero, err)
+				return
+			}
+
+			items := data
+			for _, item := range items {
+				if !yield(item, nil) {
+					return
+				}
+			}
+
+			if len(items) == 0 || int64(page) >= int64(apiResponse.TotalPage) {
+				return
+			}
+
+			page++
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/requestgen/mocktest"
+)
+
+func TestListOrdersRequest_Iterate(t *testing.T) {
+	transport := mocktest.NewTransport(t)
+	for page, id := range []string{"1", "2", "3"} {
+		transport.GET("/v1/orders").
+			WithQuery("currentPage", []string{"1", "2", "3"}[page]).
+			RespondJSON(http.StatusOK, map[string]interface{}{
+				"code":        "200000",
+				"currentPage": page + 1,
+				"totalPage":   3,
+				"data":        []map[string]interface{}{{"id": id}},
+			})
+	}
+
+	client := NewClient()
+	client.HttpClient.Transport = transport
+
+	req := &ListOrdersRequest{client: client}
+
+	var ids []string
+	for order, err := range req.Iterate(context.Background()) {
+		if !assert.NoError(t, err) {
+			break
+		}
+		ids = append(ids, order.Id)
+	}
+
+	assert.Equal(t, []string{"1", "2", "3"}, ids)
+	assert.Equal(t, 3, *req.currentPage)
+}
+
+func TestListOrdersRequest_Iterate_Error(t *testing.T) {
+	transport := mocktest.NewTransport(t)
+	transport.GET("/v1/orders").WithQuery("currentPage", "2").RespondJSON(http.StatusOK, map[string]interface{}{
+		"code":      "200000",
+		"totalPage": 5,
+		"data":      []map[string]interface{}{{"id": "a"}, {"id": "b"}},
+	})
+	transport.GET("/v1/orders").WithQuery("currentPage", "3").RespondStatus(http.StatusInternalServerError)
+
+	client := NewClient()
+	client.HttpClient.Transport = transport
+
+	// the iteration starts from the current page, and stops on the first error
+	req := &ListOrdersRequest{client: client}
+	req.CurrentPage(2)
+
+	var ids []string
+	var lastErr error
+	for order, err := range req.Iterate(context.Background()) {
+		if err != nil {
+			lastErr = err
+			break
+		}
+		ids = append(ids, order.Id)
+	}
+
+	assert.Equal(t, []string{"a", "b"}, ids)
+	assert.Error(t, lastErr)
+
+	// breaking the loop stops the iteration
+	ids = nil
+	req.CurrentPage(2)
+	for order := range req.Iterate(context.Background()) {
+		ids = append(ids, order.Id)
+		break
+	}
+	assert.Equal(t, []string{"a"}, ids)
+}
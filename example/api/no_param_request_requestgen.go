@@ -1,4 +1,4 @@
-// Code generated by "requestgen -type NoParamRequest -url /v1/bullet -method GET -debug ./example/api"; DO NOT EDIT.
+// Code generated by "requestgen -type NoParamRequest -url /v1/bullet -method GET -debug"; DO NOT EDIT.
 
 package api
 
@@ -6,9 +6,11 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"github.com/c9s/requestgen"
 	"net/url"
 	"reflect"
 	"regexp"
+	"sync"
 )
 
 // GetQueryParameters builds and checks the query parameters and returns url.Values
@@ -17,7 +19,13 @@ func (n *NoParamRequest) GetQueryParameters() (url.Values, error) {
 
 	query := url.Values{}
 	for _k, _v := range params {
-		query.Add(_k, fmt.Sprintf("%v", _v))
+		if n.isVarSlice(_v) {
+			n.iterateSlice(_v, func(it interface{}) {
+				query.Add(_k+"[]", fmt.Sprintf("%v", it))
+			})
+		} else {
+			query.Add(_k, fmt.Sprintf("%v", _v))
+		}
 	}
 
 	return query, nil
@@ -69,9 +77,19 @@ func (n *NoParamRequest) GetSlugParameters() (map[string]interface{}, error) {
 	return params, nil
 }
 
+var NoParamRequestSlugReCache sync.Map
+
 func (n *NoParamRequest) applySlugsToUrl(url string, slugs map[string]string) string {
 	for _k, _v := range slugs {
-		needleRE := regexp.MustCompile(":" + _k + "\\b")
+		var needleRE *regexp.Regexp
+
+		if cached, ok := NoParamRequestSlugReCache.Load(_k); ok {
+			needleRE = cached.(*regexp.Regexp)
+		} else {
+			needleRE = regexp.MustCompile(":" + _k + "\\b")
+			NoParamRequestSlugReCache.Store(_k, needleRE)
+		}
+
 		url = needleRE.ReplaceAllString(url, _v)
 	}
 
@@ -109,27 +127,78 @@ func (n *NoParamRequest) GetSlugsMap() (map[string]string, error) {
 	return slugs, nil
 }
 
-func (n *NoParamRequest) Do(ctx context.Context) (interface{}, error) {
+// GetPath returns the request path of the API
+func (n *NoParamRequest) GetPath() string {
+	return "/v1/bullet"
+}
+
+// Do generates the request object and send the request object to the API endpoint
+func (n *NoParamRequest) Do(ctx context.Context) (_ interface{}, err error) {
+	meta := &requestgen.RequestMeta{
+		Name:         "NoParamRequest",
+		Method:       "GET",
+		PathTemplate: n.GetPath(),
+	}
+	ctx = requestgen.WithRequestMeta(ctx, meta)
+
+	ctx, span := requestgen.StartRequestSpan(ctx, meta)
+	defer func() {
+		requestgen.EndSpan(span, err)
+	}()
 
 	// no body params
 	var params interface{}
 	query := url.Values{}
 
-	apiURL := "/v1/bullet"
+	var apiURL string
+
+	apiURL = n.GetPath()
 
 	req, err := n.client.NewRequest(ctx, "GET", apiURL, query, params)
 	if err != nil {
 		return nil, err
 	}
 
+	span.AddEvent("build_request")
+
 	response, err := n.client.SendRequest(req)
+	if response != nil && response.Response != nil {
+		span.SetAttributes(requestgen.Attr(requestgen.AttrStatusCode, response.StatusCode))
+	}
+
+	span.AddEvent("send_request")
 	if err != nil {
 		return nil, err
 	}
 
 	var apiResponse interface{}
-	if err := response.DecodeJSON(&apiResponse); err != nil {
-		return nil, err
+
+	type responseUnmarshaler interface {
+		Unmarshal(data []byte) error
+	}
+
+	if unmarshaler, ok := interface{}(&apiResponse).(responseUnmarshaler); ok {
+		if err := unmarshaler.Unmarshal(response.Body); err != nil {
+			return nil, err
+		}
+	} else {
+		// The decoder is chosen by the content type of the response, JSON is used if the content type is unknown,
+		// since some API servers might not send the correct content type header.
+		if err := response.Decode(&apiResponse); err != nil {
+			return nil, err
+		}
+	}
+
+	span.AddEvent("decode_response")
+
+	type responseValidator interface {
+		Validate() error
+	}
+
+	if validator, ok := interface{}(&apiResponse).(responseValidator); ok {
+		if err := validator.Validate(); err != nil {
+			return nil, err
+		}
 	}
 	return apiResponse, nil
 }
@@ -5,11 +5,13 @@ package api
 import (
 	"encoding/json"
 	"fmt"
+	"github.com/c9s/requestgen"
 	"github.com/google/uuid"
 	"net/url"
 	"reflect"
 	"regexp"
 	"strconv"
+	"sync"
 	"time"
 )
 
@@ -22,8 +24,10 @@ func (p *PlaceOrderRequest) Page(page int64) *PlaceOrderRequest {
 }
 
 /*
- * ClientOrderID sets clientOrderID A combination of case-sensitive alphanumerics, all numbers, or all letters of up to 32 characters.
- */
+  - ClientOrderID sets clientOrderID A combination of case-sensitive alphanumerics,
+
+all numbers, or all letters of up to 32 characters.
+*/
 func (p *PlaceOrderRequest) ClientOrderID(clientOrderID string) *PlaceOrderRequest {
 	p.clientOrderID = &clientOrderID
 	return p
@@ -38,8 +42,10 @@ func (p *PlaceOrderRequest) Symbol(symbol string) *PlaceOrderRequest {
 }
 
 /*
- * Tag sets A combination of case-sensitive alphanumerics, all numbers, or all letters of up to 8 characters.
- */
+  - Tag sets A combination of case-sensitive alphanumerics, all numbers,
+
+or all letters of up to 8 characters.
+*/
 func (p *PlaceOrderRequest) Tag(tag string) *PlaceOrderRequest {
 	p.tag = &tag
 	return p
@@ -170,7 +176,7 @@ func (p *PlaceOrderRequest) GetParameters() (map[string]interface{}, error) {
 
 	// TEMPLATE check-required
 	if len(symbol) == 0 {
-		return nil, fmt.Errorf("symbol is required, empty string given")
+		return nil, requestgen.NewValidationError("symbol", "symbol is required, empty string given")
 	}
 	// END TEMPLATE check-required
 
@@ -194,7 +200,7 @@ func (p *PlaceOrderRequest) GetParameters() (map[string]interface{}, error) {
 
 	// TEMPLATE check-required
 	if len(side) == 0 {
-		return nil, fmt.Errorf("side is required, empty string given")
+		return nil, requestgen.NewValidationError("side", "side is required, empty string given")
 	}
 	// END TEMPLATE check-required
 
@@ -204,7 +210,7 @@ func (p *PlaceOrderRequest) GetParameters() (map[string]interface{}, error) {
 		params["side"] = side
 
 	default:
-		return nil, fmt.Errorf("side value %v is invalid", side)
+		return nil, requestgen.NewValidationError("side", "side value %v is invalid", side)
 
 	}
 	// END TEMPLATE check-valid-values
@@ -227,7 +233,7 @@ func (p *PlaceOrderRequest) GetParameters() (map[string]interface{}, error) {
 		params["ordType"] = ordType
 
 	default:
-		return nil, fmt.Errorf("ordType value %v is invalid", ordType)
+		return nil, requestgen.NewValidationError("ordType", "ordType value %v is invalid", ordType)
 
 	}
 	// END TEMPLATE check-valid-values
@@ -272,7 +278,7 @@ func (p *PlaceOrderRequest) GetParameters() (map[string]interface{}, error) {
 			params["timeInForce"] = timeInForce
 
 		default:
-			return nil, fmt.Errorf("timeInForce value %v is invalid", timeInForce)
+			return nil, requestgen.NewValidationError("timeInForce", "timeInForce value %v is invalid", timeInForce)
 
 		}
 		// END TEMPLATE check-valid-values
@@ -372,9 +378,19 @@ func (p *PlaceOrderRequest) GetSlugParameters() (map[string]interface{}, error)
 	return params, nil
 }
 
+var PlaceOrderRequestSlugReCache sync.Map
+
 func (p *PlaceOrderRequest) applySlugsToUrl(url string, slugs map[string]string) string {
 	for _k, _v := range slugs {
-		needleRE := regexp.MustCompile(":" + _k + "\\b")
+		var needleRE *regexp.Regexp
+
+		if cached, ok := PlaceOrderRequestSlugReCache.Load(_k); ok {
+			needleRE = cached.(*regexp.Regexp)
+		} else {
+			needleRE = regexp.MustCompile(":" + _k + "\\b")
+			PlaceOrderRequestSlugReCache.Store(_k, needleRE)
+		}
+
 		url = needleRE.ReplaceAllString(url, _v)
 	}
 
@@ -0,0 +1,23 @@
+package api
+
+import "github.com/c9s/requestgen"
+
+type StopOrderResponse struct {
+	OrderID string `json:"orderId"`
+}
+
+//go:generate go run ../../cmd/requestgen -type PlaceStopOrderRequest -url /v1/stop-order -method POST -responseType .Response -responseDataField Data -responseDataType .StopOrderResponse
+type PlaceStopOrderRequest struct {
+	client requestgen.APIClient
+
+	// clientOrderID is the unique order id, it's generated once and sent again when the order is retried
+	clientOrderID *string `param:"clientOid" idempotencyKey:"param"`
+
+	symbol string `param:"symbol,required"`
+
+	side SideType `param:"side,required" validValues:"buy,sell"`
+
+	size string `param:"size,required"`
+
+	stopPrice string `param:"stopPrice,required"`
+}
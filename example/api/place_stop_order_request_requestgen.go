@@ -0,0 +1,321 @@
+// Code generated by "requestgen -type PlaceStopOrderRequest -url /v1/stop-order -method POST -responseType .Response -responseDataField Data -responseDataType .StopOrderResponse"; DO NOT EDIT.
+
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/c9s/requestgen"
+	"github.com/google/uuid"
+	"net/url"
+	"reflect"
+	"regexp"
+	"sync"
+)
+
+/*
+ * ClientOrderID sets clientOrderID is the unique order id, it's generated once and sent again when the order is retried
+ */
+func (p *PlaceStopOrderRequest) ClientOrderID(clientOrderID string) *PlaceStopOrderRequest {
+	p.clientOrderID = &clientOrderID
+	return p
+}
+
+/*
+ * Symbol sets
+ */
+func (p *PlaceStopOrderRequest) Symbol(symbol string) *PlaceStopOrderRequest {
+	p.symbol = symbol
+	return p
+}
+
+/*
+ * Side sets
+ */
+func (p *PlaceStopOrderRequest) Side(side SideType) *PlaceStopOrderRequest {
+	p.side = side
+	return p
+}
+
+/*
+ * Size sets
+ */
+func (p *PlaceStopOrderRequest) Size(size string) *PlaceStopOrderRequest {
+	p.size = size
+	return p
+}
+
+/*
+ * StopPrice sets
+ */
+func (p *PlaceStopOrderRequest) StopPrice(stopPrice string) *PlaceStopOrderRequest {
+	p.stopPrice = stopPrice
+	return p
+}
+
+// GetQueryParameters builds and checks the query parameters and returns url.Values
+func (p *PlaceStopOrderRequest) GetQueryParameters() (url.Values, error) {
+	var params = map[string]interface{}{}
+
+	query := url.Values{}
+	for _k, _v := range params {
+		if p.isVarSlice(_v) {
+			p.iterateSlice(_v, func(it interface{}) {
+				query.Add(_k+"[]", fmt.Sprintf("%v", it))
+			})
+		} else {
+			query.Add(_k, fmt.Sprintf("%v", _v))
+		}
+	}
+
+	return query, nil
+}
+
+// GetParameters builds and checks the parameters and return the result in a map object
+func (p *PlaceStopOrderRequest) GetParameters() (map[string]interface{}, error) {
+	var params = map[string]interface{}{}
+	// check clientOrderID field -> json key clientOid
+	// the idempotency key is generated once and kept in the request
+	params["clientOid"] = p.GetIdempotencyKey()
+	// check symbol field -> json key symbol
+	symbol := p.symbol
+
+	// TEMPLATE check-required
+	if len(symbol) == 0 {
+		return nil, requestgen.NewValidationError("symbol", "symbol is required, empty string given")
+	}
+	// END TEMPLATE check-required
+
+	// assign parameter of symbol
+	params["symbol"] = symbol
+	// check side field -> json key side
+	side := p.side
+
+	// TEMPLATE check-required
+	if len(side) == 0 {
+		return nil, requestgen.NewValidationError("side", "side is required, empty string given")
+	}
+	// END TEMPLATE check-required
+
+	// TEMPLATE check-valid-values
+	switch side {
+	case "buy", "sell":
+		params["side"] = side
+
+	default:
+		return nil, requestgen.NewValidationError("side", "side value %v is invalid", side)
+
+	}
+	// END TEMPLATE check-valid-values
+
+	// assign parameter of side
+	params["side"] = side
+	// check size field -> json key size
+	size := p.size
+
+	// TEMPLATE check-required
+	if len(size) == 0 {
+		return nil, requestgen.NewValidationError("size", "size is required, empty string given")
+	}
+	// END TEMPLATE check-required
+
+	// assign parameter of size
+	params["size"] = size
+	// check stopPrice field -> json key stopPrice
+	stopPrice := p.stopPrice
+
+	// TEMPLATE check-required
+	if len(stopPrice) == 0 {
+		return nil, requestgen.NewValidationError("stopPrice", "stopPrice is required, empty string given")
+	}
+	// END TEMPLATE check-required
+
+	// assign parameter of stopPrice
+	params["stopPrice"] = stopPrice
+
+	return params, nil
+}
+
+// GetParametersQuery converts the parameters from GetParameters into the url.Values format
+func (p *PlaceStopOrderRequest) GetParametersQuery() (url.Values, error) {
+	query := url.Values{}
+
+	params, err := p.GetParameters()
+	if err != nil {
+		return query, err
+	}
+
+	for _k, _v := range params {
+		if p.isVarSlice(_v) {
+			p.iterateSlice(_v, func(it interface{}) {
+				query.Add(_k+"[]", fmt.Sprintf("%v", it))
+			})
+		} else {
+			query.Add(_k, fmt.Sprintf("%v", _v))
+		}
+	}
+
+	return query, nil
+}
+
+// GetParametersJSON converts the parameters from GetParameters into the JSON format
+func (p *PlaceStopOrderRequest) GetParametersJSON() ([]byte, error) {
+	params, err := p.GetParameters()
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(params)
+}
+
+// GetSlugParameters builds and checks the slug parameters and return the result in a map object
+func (p *PlaceStopOrderRequest) GetSlugParameters() (map[string]interface{}, error) {
+	var params = map[string]interface{}{}
+
+	return params, nil
+}
+
+var PlaceStopOrderRequestSlugReCache sync.Map
+
+func (p *PlaceStopOrderRequest) applySlugsToUrl(url string, slugs map[string]string) string {
+	for _k, _v := range slugs {
+		var needleRE *regexp.Regexp
+
+		if cached, ok := PlaceStopOrderRequestSlugReCache.Load(_k); ok {
+			needleRE = cached.(*regexp.Regexp)
+		} else {
+			needleRE = regexp.MustCompile(":" + _k + "\\b")
+			PlaceStopOrderRequestSlugReCache.Store(_k, needleRE)
+		}
+
+		url = needleRE.ReplaceAllString(url, _v)
+	}
+
+	return url
+}
+
+func (p *PlaceStopOrderRequest) iterateSlice(slice interface{}, _f func(it interface{})) {
+	sliceValue := reflect.ValueOf(slice)
+	for _i := 0; _i < sliceValue.Len(); _i++ {
+		it := sliceValue.Index(_i).Interface()
+		_f(it)
+	}
+}
+
+func (p *PlaceStopOrderRequest) isVarSlice(_v interface{}) bool {
+	rt := reflect.TypeOf(_v)
+	switch rt.Kind() {
+	case reflect.Slice:
+		return true
+	}
+	return false
+}
+
+func (p *PlaceStopOrderRequest) GetSlugsMap() (map[string]string, error) {
+	slugs := map[string]string{}
+	params, err := p.GetSlugParameters()
+	if err != nil {
+		return slugs, nil
+	}
+
+	for _k, _v := range params {
+		slugs[_k] = fmt.Sprintf("%v", _v)
+	}
+
+	return slugs, nil
+}
+
+// GetIdempotencyKey returns the idempotency key of the request. A new key is generated and kept in clientOrderID
+// if it's empty, so the retries of the same request object send the same key. The key can be restored with ClientOrderID.
+func (p *PlaceStopOrderRequest) GetIdempotencyKey() string {
+	if p.clientOrderID == nil || len(*p.clientOrderID) == 0 {
+		key := uuid.New().String()
+		p.clientOrderID = &key
+	}
+
+	return *p.clientOrderID
+}
+
+// GetPath returns the request path of the API
+func (p *PlaceStopOrderRequest) GetPath() string {
+	return "/v1/stop-order"
+}
+
+// Do generates the request object and send the request object to the API endpoint
+func (p *PlaceStopOrderRequest) Do(ctx context.Context) (_ *StopOrderResponse, err error) {
+	meta := &requestgen.RequestMeta{
+		Name:           "PlaceStopOrderRequest",
+		Method:         "POST",
+		PathTemplate:   p.GetPath(),
+		IdempotencyKey: p.GetIdempotencyKey(),
+	}
+	ctx = requestgen.WithRequestMeta(ctx, meta)
+
+	ctx, span := requestgen.StartRequestSpan(ctx, meta)
+	defer func() {
+		requestgen.EndSpan(span, err)
+	}()
+
+	params, err := p.GetParameters()
+	if err != nil {
+		return nil, err
+	}
+	query := url.Values{}
+
+	var apiURL string
+
+	apiURL = p.GetPath()
+
+	req, err := p.client.NewRequest(ctx, "POST", apiURL, query, params)
+	if err != nil {
+		return nil, err
+	}
+
+	span.AddEvent("build_request")
+
+	response, err := p.client.SendRequest(req)
+	if response != nil && response.Response != nil {
+		span.SetAttributes(requestgen.Attr(requestgen.AttrStatusCode, response.StatusCode))
+	}
+
+	span.AddEvent("send_request")
+	if err != nil {
+		return nil, err
+	}
+
+	var apiResponse Response
+
+	type responseUnmarshaler interface {
+		Unmarshal(data []byte) error
+	}
+
+	if unmarshaler, ok := interface{}(&apiResponse).(responseUnmarshaler); ok {
+		if err := unmarshaler.Unmarshal(response.Body); err != nil {
+			return nil, err
+		}
+	} else {
+		// The decoder is chosen by the content type of the response, JSON is used if the content type is unknown,
+		// since some API servers might not send the correct content type header.
+		if err := response.Decode(&apiResponse); err != nil {
+			return nil, err
+		}
+	}
+
+	span.AddEvent("decode_response")
+
+	type responseValidator interface {
+		Validate() error
+	}
+
+	if validator, ok := interface{}(&apiResponse).(responseValidator); ok {
+		if err := validator.Validate(); err != nil {
+			return nil, err
+		}
+	}
+	var data StopOrderResponse
+	if err := json.Unmarshal(apiResponse.Data, &data); err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
@@ -0,0 +1,68 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/requestgen"
+	"github.com/c9s/requestgen/mocktest"
+)
+
+func TestPlaceStopOrderRequest_IdempotencyKey(t *testing.T) {
+	transport := mocktest.NewTransport(t)
+	route := transport.POST("/v1/stop-order").
+		RespondStatus(http.StatusServiceUnavailable).
+		RespondJSON(http.StatusOK, map[string]interface{}{
+			"code": "200000",
+			"data": map[string]string{"orderId": "5c35c02703aa673ceec2a168"},
+		})
+
+	observer := &recordingObserver{}
+	client := NewClient()
+	client.HttpClient.Transport = transport
+	client.Observer = observer
+	client.RetryPolicy = &requestgen.ExponentialBackoff{MaxAttempts: 2, InitialInterval: time.Millisecond}
+
+	req := &PlaceStopOrderRequest{client: client}
+	req.Symbol("BTC-USDT").Side(SideTypeBuy).Size("0.1").StopPrice("30000")
+
+	order, err := req.Do(context.Background())
+	if assert.NoError(t, err) {
+		assert.Equal(t, "5c35c02703aa673ceec2a168", order.OrderID)
+	}
+
+	key := req.GetIdempotencyKey()
+	assert.NotEmpty(t, key)
+
+	// the retried request carries the same client order id
+	if assert.Len(t, route.Requests(), 2) {
+		for _, r := range route.Requests() {
+			var params map[string]interface{}
+			assert.NoError(t, json.Unmarshal(r.Body, &params))
+			assert.Equal(t, key, params["clientOid"])
+		}
+	}
+
+	if assert.Len(t, observer.observations, 2) {
+		assert.Equal(t, key, observer.observations[0].IdempotencyKey)
+		assert.Equal(t, key, observer.observations[1].IdempotencyKey)
+	}
+
+	// the key is kept in the request object
+	params, err := req.GetParameters()
+	if assert.NoError(t, err) {
+		assert.Equal(t, key, params["clientOid"])
+	}
+
+	// the restored key is not replaced
+	req.ClientOrderID("my-order-1")
+	assert.Equal(t, "my-order-1", req.GetIdempotencyKey())
+
+	another := &PlaceStopOrderRequest{client: client}
+	assert.NotEqual(t, key, another.GetIdempotencyKey())
+}
@@ -8,8 +8,12 @@ import (
 	"net/url"
 	"reflect"
 	"regexp"
+	"sync"
 )
 
+/*
+ * Id sets
+ */
 func (q *QueryOrderRequest) Id(id []int) *QueryOrderRequest {
 	q.id = id
 	return q
@@ -94,9 +98,19 @@ func (q *QueryOrderRequest) GetSlugParameters() (map[string]interface{}, error)
 	return params, nil
 }
 
+var QueryOrderRequestSlugReCache sync.Map
+
 func (q *QueryOrderRequest) applySlugsToUrl(url string, slugs map[string]string) string {
 	for _k, _v := range slugs {
-		needleRE := regexp.MustCompile(":" + _k + "\\b")
+		var needleRE *regexp.Regexp
+
+		if cached, ok := QueryOrderRequestSlugReCache.Load(_k); ok {
+			needleRE = cached.(*regexp.Regexp)
+		} else {
+			needleRE = regexp.MustCompile(":" + _k + "\\b")
+			QueryOrderRequestSlugReCache.Store(_k, needleRE)
+		}
+
 		url = needleRE.ReplaceAllString(url, _v)
 	}
 
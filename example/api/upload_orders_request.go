@@ -0,0 +1,17 @@
+package api
+
+import (
+	"io"
+
+	"github.com/c9s/requestgen"
+)
+
+//go:generate go run ../../cmd/requestgen -type UploadOrdersRequest -url /v1/orders/upload -method POST -responseType .Response
+type UploadOrdersRequest struct {
+	client requestgen.APIClient
+
+	symbol string `param:"symbol,required"`
+
+	// file is the CSV file of the orders
+	file io.Reader `param:"file,multipart,required,filename=orders.csv"`
+}
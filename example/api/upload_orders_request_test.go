@@ -0,0 +1,54 @@
+package api
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/requestgen"
+	"github.com/c9s/requestgen/mocktest"
+)
+
+func TestUploadOrdersRequest_Multipart(t *testing.T) {
+	csv := "side,price,size\nbuy,100.0,0.1\n"
+
+	transport := mocktest.NewTransport(t)
+	route := transport.POST("/v1/orders/upload").
+		RespondJSON(http.StatusOK, map[string]interface{}{"code": "200000"})
+
+	client := NewClient()
+	client.HttpClient.Transport = transport
+
+	req := &UploadOrdersRequest{client: client}
+	resp, err := req.Symbol("BTC-USDT").File(strings.NewReader(csv)).Do(context.Background())
+	if assert.NoError(t, err) {
+		assert.Equal(t, "200000", resp.Code)
+	}
+
+	captured := route.LastRequest()
+	if !assert.NotNil(t, captured) {
+		return
+	}
+
+	assert.True(t, strings.HasPrefix(captured.Header.Get("Content-Type"), "multipart/form-data; boundary="))
+	if assert.NoError(t, captured.ParseMultipartForm(1<<20)) {
+		assert.Equal(t, "BTC-USDT", captured.FormValue("symbol"))
+
+		file, header, err := captured.FormFile("file")
+		if assert.NoError(t, err) {
+			content, _ := io.ReadAll(file)
+			assert.Equal(t, "orders.csv", header.Filename)
+			assert.Equal(t, csv, string(content))
+		}
+	}
+}
+
+func TestUploadOrdersRequest_MissingFile(t *testing.T) {
+	req := &UploadOrdersRequest{client: NewClient()}
+	_, err := req.Symbol("BTC-USDT").Do(context.Background())
+	assert.True(t, requestgen.IsValidationError(err))
+}
@@ -2,7 +2,7 @@ package api
 
 import "github.com/c9s/requestgen"
 
-//go:generate go run ../../../cmd/requestgen -method GET -dynamicPath -debug -type DynamicPathRequest -responseType NoParamResponse -rateLimiter 5+10/2s
+//go:generate go run ../../../cmd/requestgen -method GET -dynamicPath -debug -type DynamicPathRequest -responseType NoParamResponse -rateLimiter 5+10/2s -adaptiveRateLimit
 type DynamicPathRequest struct {
 	client requestgen.APIClient
 }
@@ -1,4 +1,4 @@
-// Code generated by "requestgen -method GET -dynamicPath -debug -type DynamicPathRequest -responseType NoParamResponse -rateLimiter 5+10/2s"; DO NOT EDIT.
+// Code generated by "requestgen -method GET -dynamicPath -debug -type DynamicPathRequest -responseType NoParamResponse -rateLimiter 5+10/2s -adaptiveRateLimit"; DO NOT EDIT.
 
 package api
 
@@ -6,10 +6,13 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"github.com/c9s/requestgen"
 	"golang.org/x/time/rate"
 	"net/url"
 	"reflect"
 	"regexp"
+	"sync"
+	"time"
 )
 
 var DynamicPathRequestLimiter = rate.NewLimiter(5, 5)
@@ -78,9 +81,19 @@ func (r *DynamicPathRequest) GetSlugParameters() (map[string]interface{}, error)
 	return params, nil
 }
 
+var DynamicPathRequestSlugReCache sync.Map
+
 func (r *DynamicPathRequest) applySlugsToUrl(url string, slugs map[string]string) string {
 	for _k, _v := range slugs {
-		needleRE := regexp.MustCompile(":" + _k + "\\b")
+		var needleRE *regexp.Regexp
+
+		if cached, ok := DynamicPathRequestSlugReCache.Load(_k); ok {
+			needleRE = cached.(*regexp.Regexp)
+		} else {
+			needleRE = regexp.MustCompile(":" + _k + "\\b")
+			DynamicPathRequestSlugReCache.Store(_k, needleRE)
+		}
+
 		url = needleRE.ReplaceAllString(url, _v)
 	}
 
@@ -124,10 +137,30 @@ func (r *DynamicPathRequest) GetPath() string {
 }
 
 // Do generates the request object and send the request object to the API endpoint
-func (r *DynamicPathRequest) Do(ctx context.Context) (*NoParamResponse, error) {
+func (r *DynamicPathRequest) Do(ctx context.Context) (_ *NoParamResponse, err error) {
+	meta := &requestgen.RequestMeta{
+		Name:         "DynamicPathRequest",
+		Method:       "GET",
+		PathTemplate: r.GetPath(),
+	}
+	ctx = requestgen.WithRequestMeta(ctx, meta)
+
+	ctx, span := requestgen.StartRequestSpan(ctx, meta)
+	defer func() {
+		requestgen.EndSpan(span, err)
+	}()
+
+	waitStart := time.Now()
 	if err := DynamicPathRequestLimiter.Wait(ctx); err != nil {
+		requestgen.ObserveRateLimitWait(r.client, meta, time.Since(waitStart), err)
 		return nil, err
 	}
+	requestgen.ObserveRateLimitWait(r.client, meta, time.Since(waitStart), nil)
+
+	if err := requestgen.WaitAdaptiveRateLimit(ctx, r.client, meta); err != nil {
+		return nil, err
+	}
+	span.AddEvent("rate_limit_wait", requestgen.Attr(requestgen.AttrRateLimitWait, meta.RateLimitWait))
 
 	// no body params
 	var params interface{}
@@ -151,7 +184,14 @@ func (r *DynamicPathRequest) Do(ctx context.Context) (*NoParamResponse, error) {
 		return nil, err
 	}
 
+	span.AddEvent("build_request")
+
 	response, err := r.client.SendRequest(req)
+	if response != nil && response.Response != nil {
+		span.SetAttributes(requestgen.Attr(requestgen.AttrStatusCode, response.StatusCode))
+	}
+
+	span.AddEvent("send_request")
 	if err != nil {
 		return nil, err
 	}
@@ -167,14 +207,15 @@ func (r *DynamicPathRequest) Do(ctx context.Context) (*NoParamResponse, error) {
 			return nil, err
 		}
 	} else {
-		// The line below checks the content type, however, some API server might not send the correct content type header,
-		// Hence, this is commented for backward compatibility
-		// response.IsJSON()
-		if err := response.DecodeJSON(&apiResponse); err != nil {
+		// The decoder is chosen by the content type of the response, JSON is used if the content type is unknown,
+		// since some API servers might not send the correct content type header.
+		if err := response.Decode(&apiResponse); err != nil {
 			return nil, err
 		}
 	}
 
+	span.AddEvent("decode_response")
+
 	type responseValidator interface {
 		Validate() error
 	}
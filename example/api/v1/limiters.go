@@ -0,0 +1,3 @@
+package api
+
+//go:generate go run ../../../cmd/requestgen -declareSharedRateLimiter Public=20+10/1s
@@ -6,7 +6,7 @@ type NoParamResponse struct {
 	ID string `json:"id"`
 }
 
-//go:generate go run ../../../cmd/requestgen -method GET -url /v1/bullet -debug -type NoParamRequest -responseType NoParamResponse -sharedRateLimiterTypeName DynamicPathRequest
+//go:generate go run ../../../cmd/requestgen -method GET -url /v1/bullet -debug -type NoParamRequest -responseType NoParamResponse -sharedRateLimiterTypeName DynamicPathRequest -weight 2
 type NoParamRequest struct {
 	client requestgen.APIClient
 }
@@ -1,4 +1,4 @@
-// Code generated by "requestgen -method GET -url /v1/bullet -debug -type NoParamRequest -responseType NoParamResponse -sharedRateLimiterTypeName DynamicPathRequest"; DO NOT EDIT.
+// Code generated by "requestgen -method GET -url /v1/bullet -debug -type NoParamRequest -responseType NoParamResponse -sharedRateLimiterTypeName DynamicPathRequest -weight 2"; DO NOT EDIT.
 
 package api
 
@@ -6,9 +6,12 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"github.com/c9s/requestgen"
 	"net/url"
 	"reflect"
 	"regexp"
+	"sync"
+	"time"
 )
 
 // GetQueryParameters builds and checks the query parameters and returns url.Values
@@ -75,9 +78,19 @@ func (n *NoParamRequest) GetSlugParameters() (map[string]interface{}, error) {
 	return params, nil
 }
 
+var NoParamRequestSlugReCache sync.Map
+
 func (n *NoParamRequest) applySlugsToUrl(url string, slugs map[string]string) string {
 	for _k, _v := range slugs {
-		needleRE := regexp.MustCompile(":" + _k + "\\b")
+		var needleRE *regexp.Regexp
+
+		if cached, ok := NoParamRequestSlugReCache.Load(_k); ok {
+			needleRE = cached.(*regexp.Regexp)
+		} else {
+			needleRE = regexp.MustCompile(":" + _k + "\\b")
+			NoParamRequestSlugReCache.Store(_k, needleRE)
+		}
+
 		url = needleRE.ReplaceAllString(url, _v)
 	}
 
@@ -121,10 +134,27 @@ func (n *NoParamRequest) GetPath() string {
 }
 
 // Do generates the request object and send the request object to the API endpoint
-func (n *NoParamRequest) Do(ctx context.Context) (*NoParamResponse, error) {
-	if err := DynamicPathRequestLimiter.Wait(ctx); err != nil {
+func (n *NoParamRequest) Do(ctx context.Context) (_ *NoParamResponse, err error) {
+	meta := &requestgen.RequestMeta{
+		Name:         "NoParamRequest",
+		Method:       "GET",
+		PathTemplate: n.GetPath(),
+		Weight:       2,
+	}
+	ctx = requestgen.WithRequestMeta(ctx, meta)
+
+	ctx, span := requestgen.StartRequestSpan(ctx, meta)
+	defer func() {
+		requestgen.EndSpan(span, err)
+	}()
+
+	waitStart := time.Now()
+	if err := DynamicPathRequestLimiter.WaitN(ctx, meta.Weight); err != nil {
+		requestgen.ObserveRateLimitWait(n.client, meta, time.Since(waitStart), err)
 		return nil, err
 	}
+	requestgen.ObserveRateLimitWait(n.client, meta, time.Since(waitStart), nil)
+	span.AddEvent("rate_limit_wait", requestgen.Attr(requestgen.AttrRateLimitWait, meta.RateLimitWait))
 
 	// no body params
 	var params interface{}
@@ -139,7 +169,14 @@ func (n *NoParamRequest) Do(ctx context.Context) (*NoParamResponse, error) {
 		return nil, err
 	}
 
+	span.AddEvent("build_request")
+
 	response, err := n.client.SendRequest(req)
+	if response != nil && response.Response != nil {
+		span.SetAttributes(requestgen.Attr(requestgen.AttrStatusCode, response.StatusCode))
+	}
+
+	span.AddEvent("send_request")
 	if err != nil {
 		return nil, err
 	}
@@ -155,14 +192,15 @@ func (n *NoParamRequest) Do(ctx context.Context) (*NoParamResponse, error) {
 			return nil, err
 		}
 	} else {
-		// The line below checks the content type, however, some API server might not send the correct content type header,
-		// Hence, this is commented for backward compatibility
-		// response.IsJSON()
-		if err := response.DecodeJSON(&apiResponse); err != nil {
+		// The decoder is chosen by the content type of the response, JSON is used if the content type is unknown,
+		// since some API servers might not send the correct content type header.
+		if err := response.Decode(&apiResponse); err != nil {
 			return nil, err
 		}
 	}
 
+	span.AddEvent("decode_response")
+
 	type responseValidator interface {
 		Validate() error
 	}
@@ -18,7 +18,7 @@ func (n ResponseValidator) Validate() error {
 	return nil
 }
 
-//go:generate go run ../../../cmd/requestgen -method GET -url /v1/bullet -debug -type ResponseValidatorRequest -responseType ResponseValidator
+//go:generate go run ../../../cmd/requestgen -method GET -url /v1/bullet -debug -type ResponseValidatorRequest -responseType ResponseValidator -sharedRateLimiterTypeName Public -clientRateLimiter
 type ResponseValidatorRequest struct {
 	client requestgen.APIClient
 }
@@ -1,4 +1,4 @@
-// Code generated by "requestgen -method GET -url /v1/bullet -debug -type ResponseValidatorRequest -responseType ResponseValidator"; DO NOT EDIT.
+// Code generated by "requestgen -method GET -url /v1/bullet -debug -type ResponseValidatorRequest -responseType ResponseValidator -sharedRateLimiterTypeName Public -clientRateLimiter"; DO NOT EDIT.
 
 package api
 
@@ -6,9 +6,12 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"github.com/c9s/requestgen"
 	"net/url"
 	"reflect"
 	"regexp"
+	"sync"
+	"time"
 )
 
 // GetQueryParameters builds and checks the query parameters and returns url.Values
@@ -75,9 +78,19 @@ func (r *ResponseValidatorRequest) GetSlugParameters() (map[string]interface{},
 	return params, nil
 }
 
+var ResponseValidatorRequestSlugReCache sync.Map
+
 func (r *ResponseValidatorRequest) applySlugsToUrl(url string, slugs map[string]string) string {
 	for _k, _v := range slugs {
-		needleRE := regexp.MustCompile(":" + _k + "\\b")
+		var needleRE *regexp.Regexp
+
+		if cached, ok := ResponseValidatorRequestSlugReCache.Load(_k); ok {
+			needleRE = cached.(*regexp.Regexp)
+		} else {
+			needleRE = regexp.MustCompile(":" + _k + "\\b")
+			ResponseValidatorRequestSlugReCache.Store(_k, needleRE)
+		}
+
 		url = needleRE.ReplaceAllString(url, _v)
 	}
 
@@ -121,7 +134,28 @@ func (r *ResponseValidatorRequest) GetPath() string {
 }
 
 // Do generates the request object and send the request object to the API endpoint
-func (r *ResponseValidatorRequest) Do(ctx context.Context) (*ResponseValidator, error) {
+func (r *ResponseValidatorRequest) Do(ctx context.Context) (_ *ResponseValidator, err error) {
+	meta := &requestgen.RequestMeta{
+		Name:         "ResponseValidatorRequest",
+		Method:       "GET",
+		PathTemplate: r.GetPath(),
+	}
+	ctx = requestgen.WithRequestMeta(ctx, meta)
+
+	ctx, span := requestgen.StartRequestSpan(ctx, meta)
+	defer func() {
+		requestgen.EndSpan(span, err)
+	}()
+
+	rateLimiter := requestgen.ClientRateLimiter(r.client, "Public", PublicLimiter)
+
+	waitStart := time.Now()
+	if err := rateLimiter.Wait(ctx); err != nil {
+		requestgen.ObserveRateLimitWait(r.client, meta, time.Since(waitStart), err)
+		return nil, err
+	}
+	requestgen.ObserveRateLimitWait(r.client, meta, time.Since(waitStart), nil)
+	span.AddEvent("rate_limit_wait", requestgen.Attr(requestgen.AttrRateLimitWait, meta.RateLimitWait))
 
 	// no body params
 	var params interface{}
@@ -136,7 +170,14 @@ func (r *ResponseValidatorRequest) Do(ctx context.Context) (*ResponseValidator,
 		return nil, err
 	}
 
+	span.AddEvent("build_request")
+
 	response, err := r.client.SendRequest(req)
+	if response != nil && response.Response != nil {
+		span.SetAttributes(requestgen.Attr(requestgen.AttrStatusCode, response.StatusCode))
+	}
+
+	span.AddEvent("send_request")
 	if err != nil {
 		return nil, err
 	}
@@ -152,14 +193,15 @@ func (r *ResponseValidatorRequest) Do(ctx context.Context) (*ResponseValidator,
 			return nil, err
 		}
 	} else {
-		// The line below checks the content type, however, some API server might not send the correct content type header,
-		// Hence, this is commented for backward compatibility
-		// response.IsJSON()
-		if err := response.DecodeJSON(&apiResponse); err != nil {
+		// The decoder is chosen by the content type of the response, JSON is used if the content type is unknown,
+		// since some API servers might not send the correct content type header.
+		if err := response.Decode(&apiResponse); err != nil {
 			return nil, err
 		}
 	}
 
+	span.AddEvent("decode_response")
+
 	type responseValidator interface {
 		Validate() error
 	}
@@ -0,0 +1,81 @@
+package requestgen
+
+import (
+	"sync"
+
+	"golang.org/x/time/rate"
+)
+
+// RateLimiterRegistry holds the rate limiters of an API client, keyed by the request type name or the shared rate limiter name.
+// Attaching a registry to each API client gives each client, e.g. each account with its own quota, its own budget.
+type RateLimiterRegistry struct {
+	mu       sync.Mutex
+	limiters map[string]*rate.Limiter
+}
+
+// NewRateLimiterRegistry creates an empty RateLimiterRegistry
+func NewRateLimiterRegistry() *RateLimiterRegistry {
+	return &RateLimiterRegistry{limiters: make(map[string]*rate.Limiter)}
+}
+
+// Get returns the rate limiter of the key. When the key has no limiter yet,
+// a new limiter with the limit and the burst of the template is created, nil is returned if the template is nil.
+func (r *RateLimiterRegistry) Get(key string, template *rate.Limiter) *rate.Limiter {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if limiter, ok := r.limiters[key]; ok {
+		return limiter
+	}
+
+	if template == nil {
+		return nil
+	}
+
+	if r.limiters == nil {
+		r.limiters = make(map[string]*rate.Limiter)
+	}
+
+	limiter := rate.NewLimiter(template.Limit(), template.Burst())
+	r.limiters[key] = limiter
+	return limiter
+}
+
+// Set sets the rate limiter of the key, e.g. to give an account a different quota
+func (r *RateLimiterRegistry) Set(key string, limiter *rate.Limiter) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.limiters == nil {
+		r.limiters = make(map[string]*rate.Limiter)
+	}
+
+	r.limiters[key] = limiter
+}
+
+// RateLimitedAPIClient is implemented by the API clients that provide a RateLimiterRegistry
+type RateLimitedAPIClient interface {
+	GetRateLimiterRegistry() *RateLimiterRegistry
+}
+
+// GetRateLimiterRegistry returns the rate limiter registry of the client
+func (c *BaseAPIClient) GetRateLimiterRegistry() *RateLimiterRegistry {
+	return c.RateLimiters
+}
+
+// ClientRateLimiter returns the rate limiter of the key from the registry of the client, the fallback limiter is
+// returned if the client does not implement RateLimitedAPIClient or has no registry. The fallback limiter is also
+// the template of the new limiters in the registry.
+func ClientRateLimiter(client interface{}, key string, fallback *rate.Limiter) *rate.Limiter {
+	limited, ok := client.(RateLimitedAPIClient)
+	if !ok {
+		return fallback
+	}
+
+	registry := limited.GetRateLimiterRegistry()
+	if registry == nil {
+		return fallback
+	}
+
+	return registry.Get(key, fallback)
+}
@@ -0,0 +1,49 @@
+package requestgen
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/time/rate"
+)
+
+func TestRateLimiterRegistry(t *testing.T) {
+	template := rate.NewLimiter(10, 5)
+
+	registry := NewRateLimiterRegistry()
+	limiter := registry.Get("GetTickerRequest", template)
+	if assert.NotNil(t, limiter) {
+		assert.True(t, limiter != template, "the registry creates its own limiter")
+		assert.Equal(t, rate.Limit(10), limiter.Limit())
+		assert.Equal(t, 5, limiter.Burst())
+	}
+
+	assert.True(t, limiter == registry.Get("GetTickerRequest", template))
+	assert.Nil(t, registry.Get("Public", nil))
+
+	custom := rate.NewLimiter(1, 1)
+	registry.Set("Public", custom)
+	assert.True(t, custom == registry.Get("Public", template))
+}
+
+func TestClientRateLimiter(t *testing.T) {
+	template := rate.NewLimiter(10, 5)
+
+	// the package-level limiter is used without a registry
+	assert.True(t, template == ClientRateLimiter(&BaseAPIClient{}, "Public", template))
+	assert.True(t, template == ClientRateLimiter(struct{}{}, "Public", template))
+
+	// each client has its own limiter
+	a := &BaseAPIClient{RateLimiters: NewRateLimiterRegistry()}
+	b := &BaseAPIClient{RateLimiters: NewRateLimiterRegistry()}
+
+	limiterA := ClientRateLimiter(a, "Public", template)
+	limiterB := ClientRateLimiter(b, "Public", template)
+	assert.True(t, limiterA != limiterB)
+	assert.True(t, limiterA == ClientRateLimiter(a, "Public", template))
+
+	assert.True(t, limiterA.AllowN(time.Now(), 4))
+	assert.InDelta(t, 1, limiterA.Tokens(), 0.5)
+	assert.InDelta(t, 5, limiterB.Tokens(), 0.1)
+}
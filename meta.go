@@ -0,0 +1,54 @@
+package requestgen
+
+import (
+	"context"
+	"time"
+)
+
+type requestMetaKey struct{}
+
+// RequestMeta carries the per-request options of the generated request to the API client through the request context
+type RequestMeta struct {
+	// Name is the endpoint name, which is the request type name, e.g. GetTickerRequest
+	Name string
+
+	// Method is the HTTP method of the request
+	Method string
+
+	// PathTemplate is the request path before the slugs are substituted, e.g. /v1/orders/:orderID
+	PathTemplate string
+
+	// Coalesce makes the identical concurrent GET requests share one HTTP request, see BaseAPIClient.CoalesceRequests.
+	Coalesce bool
+
+	// CacheTTL forces the freshness lifetime of the cached response regardless of the Cache-Control header,
+	// see BaseAPIClient.ResponseCache.
+	CacheTTL time.Duration
+
+	// Weight is the number of the rate limiter tokens taken by the request, the requests without a weight take one token.
+	Weight int
+
+	// ErrorType is the error envelope prototype of the request, e.g. &APIError{}, set by the -errorType option of requestgen.
+	// The response that decodes into a failed envelope is not stored in the ResponseCache, see DecodeAPIError.
+	ErrorType error
+
+	// IdempotencyKey is the idempotency key of the request, it's kept across the retries of the same request object.
+	IdempotencyKey string
+
+	// RateLimitWait is the time spent waiting for the rate limiters before sending the request
+	RateLimitWait time.Duration
+
+	// MaxResponseBytes overrides BaseAPIClient.MaxResponseBytes when it's not zero, -1 means no limit.
+	MaxResponseBytes int64
+}
+
+// WithRequestMeta returns a copy of ctx that carries the request meta
+func WithRequestMeta(ctx context.Context, meta *RequestMeta) context.Context {
+	return context.WithValue(ctx, requestMetaKey{}, meta)
+}
+
+// RequestMetaFromContext returns the request meta carried by ctx, nil is returned if there is no request meta
+func RequestMetaFromContext(ctx context.Context) *RequestMeta {
+	meta, _ := ctx.Value(requestMetaKey{}).(*RequestMeta)
+	return meta
+}
@@ -0,0 +1,33 @@
+package requestgen
+
+import "net/http"
+
+// Doer sends the http request and returns the response
+type Doer interface {
+	Do(req *http.Request) (*Response, error)
+}
+
+// DoerFunc is an adapter to allow the use of ordinary functions as Doer
+type DoerFunc func(req *http.Request) (*Response, error)
+
+func (f DoerFunc) Do(req *http.Request) (*Response, error) {
+	return f(req)
+}
+
+// Middleware wraps the next Doer with extra behavior, e.g., logging, signing, metrics or header injection.
+type Middleware func(next Doer) Doer
+
+// Use appends the middlewares to the middleware chain of SendRequest.
+// The first middleware is the outermost one, and the middlewares are called once per SendRequest call,
+// the retries of the RetryPolicy happen inside the chain.
+func (c *BaseAPIClient) Use(middlewares ...Middleware) {
+	c.middlewares = append(c.middlewares, middlewares...)
+}
+
+func chainMiddlewares(doer Doer, middlewares []Middleware) Doer {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		doer = middlewares[i](doer)
+	}
+
+	return doer
+}
@@ -0,0 +1,39 @@
+package requestgen
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBaseAPIClient_Use(t *testing.T) {
+	var trace []string
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		trace = append(trace, "server:"+r.Header.Get("X-Request-Source"))
+		w.Write([]byte(`{}`))
+	})
+
+	client.Use(func(next Doer) Doer {
+		return DoerFunc(func(req *http.Request) (*Response, error) {
+			trace = append(trace, "outer")
+			req.Header.Set("X-Request-Source", "requestgen")
+			return next.Do(req)
+		})
+	}, func(next Doer) Doer {
+		return DoerFunc(func(req *http.Request) (*Response, error) {
+			trace = append(trace, "inner")
+			resp, err := next.Do(req)
+			trace = append(trace, "inner:done")
+			return resp, err
+		})
+	})
+
+	req, err := client.NewRequest(context.Background(), "GET", "/api/v1/ping", nil, nil)
+	assert.NoError(t, err)
+
+	_, err = client.SendRequest(req)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"outer", "inner", "server:requestgen", "inner:done"}, trace)
+}
@@ -0,0 +1,282 @@
+package mocktest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+var slugRE = regexp.MustCompile(`:(\w+)`)
+
+// Route matches the requests and replies the defined responses in sequence.
+// The last response is replied repeatedly once the sequence is used up.
+type Route struct {
+	method  string
+	path    string
+	pathRE  *regexp.Regexp
+	slugs   []string
+	query   map[string]string
+	headers map[string]string
+	fields  map[string]interface{}
+
+	matchers []func(req *Request) bool
+
+	mu        sync.Mutex
+	responses []RoundTripFunc
+	requests  []*Request
+}
+
+func newRoute(method, path string) *Route {
+	route := &Route{
+		method:  strings.ToUpper(method),
+		path:    path,
+		query:   map[string]string{},
+		headers: map[string]string{},
+		fields:  map[string]interface{}{},
+	}
+
+	var sb strings.Builder
+	sb.WriteString("^")
+	last := 0
+	for _, loc := range slugRE.FindAllStringSubmatchIndex(path, -1) {
+		sb.WriteString(regexp.QuoteMeta(path[last:loc[0]]))
+		sb.WriteString("([^/]+)")
+		route.slugs = append(route.slugs, path[loc[2]:loc[3]])
+		last = loc[1]
+	}
+	sb.WriteString(regexp.QuoteMeta(path[last:]))
+	sb.WriteString("$")
+
+	route.pathRE = regexp.MustCompile(sb.String())
+	return route
+}
+
+// WithQuery matches the requests with the given query parameter value
+func (route *Route) WithQuery(key, value string) *Route {
+	route.query[key] = value
+	return route
+}
+
+// WithHeader matches the requests with the given header value
+func (route *Route) WithHeader(key, value string) *Route {
+	route.headers[key] = value
+	return route
+}
+
+// WithJSONField matches the requests with the given JSON body field value.
+// The nested fields are referenced by the dot separated path, e.g. "orders.0.side".
+func (route *Route) WithJSONField(path string, value interface{}) *Route {
+	route.fields[path] = normalizeJSON(value)
+	return route
+}
+
+// Match matches the requests with a custom function
+func (route *Route) Match(f func(req *Request) bool) *Route {
+	route.matchers = append(route.matchers, f)
+	return route
+}
+
+// RespondWith appends a response built by the given function to the sequence
+func (route *Route) RespondWith(f RoundTripFunc) *Route {
+	route.mu.Lock()
+	route.responses = append(route.responses, f)
+	route.mu.Unlock()
+	return route
+}
+
+// RespondJSON appends a JSON response to the sequence
+func (route *Route) RespondJSON(code int, payload interface{}) *Route {
+	return route.RespondWith(func(req *http.Request) (*http.Response, error) {
+		return BuildResponseJson(code, payload), nil
+	})
+}
+
+// RespondString appends a response with the given body to the sequence
+func (route *Route) RespondString(code int, payload string) *Route {
+	return route.RespondWith(func(req *http.Request) (*http.Response, error) {
+		return BuildResponseString(code, payload), nil
+	})
+}
+
+// RespondStatus appends an empty response with the given status code to the sequence
+func (route *Route) RespondStatus(code int) *Route {
+	return route.RespondString(code, "")
+}
+
+// RespondError appends a transport error to the sequence
+func (route *Route) RespondError(err error) *Route {
+	return route.RespondWith(func(req *http.Request) (*http.Response, error) {
+		return nil, err
+	})
+}
+
+// Times repeats the last appended response, so that it's replied n times in the sequence
+func (route *Route) Times(n int) *Route {
+	route.mu.Lock()
+	defer route.mu.Unlock()
+
+	if len(route.responses) == 0 {
+		panic("mocktest: Times() is called before any response is defined")
+	}
+
+	last := route.responses[len(route.responses)-1]
+	for i := 1; i < n; i++ {
+		route.responses = append(route.responses, last)
+	}
+
+	return route
+}
+
+// Calls returns the number of the matched requests
+func (route *Route) Calls() int {
+	route.mu.Lock()
+	defer route.mu.Unlock()
+	return len(route.requests)
+}
+
+// Requests returns the captured requests
+func (route *Route) Requests() []*Request {
+	route.mu.Lock()
+	defer route.mu.Unlock()
+	return append([]*Request(nil), route.requests...)
+}
+
+// LastRequest returns the last captured request, or nil if the route is not called yet
+func (route *Route) LastRequest() *Request {
+	route.mu.Lock()
+	defer route.mu.Unlock()
+	if len(route.requests) == 0 {
+		return nil
+	}
+	return route.requests[len(route.requests)-1]
+}
+
+func (route *Route) String() string {
+	return route.method + " " + route.path
+}
+
+func (route *Route) match(req *Request) bool {
+	if route.method != req.Method {
+		return false
+	}
+
+	matches := route.pathRE.FindStringSubmatch(req.URL.Path)
+	if matches == nil {
+		return false
+	}
+
+	query := req.URL.Query()
+	for key, value := range route.query {
+		if values, ok := query[key]; !ok || !contains(values, value) {
+			return false
+		}
+	}
+
+	for key, value := range route.headers {
+		if !contains(req.Header.Values(key), value) {
+			return false
+		}
+	}
+
+	if len(route.fields) > 0 {
+		var body interface{}
+		if err := json.Unmarshal(req.Body, &body); err != nil {
+			return false
+		}
+
+		for path, value := range route.fields {
+			field, ok := lookupJSONField(body, path)
+			if !ok || !reflect.DeepEqual(field, value) {
+				return false
+			}
+		}
+	}
+
+	params := make(map[string]string, len(route.slugs))
+	for i, slug := range route.slugs {
+		params[slug] = matches[i+1]
+	}
+	req.PathParams = params
+
+	for _, matcher := range route.matchers {
+		if !matcher(req) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (route *Route) respond(req *Request) (*http.Response, error) {
+	route.mu.Lock()
+	n := len(route.requests)
+	route.requests = append(route.requests, req)
+
+	if len(route.responses) == 0 {
+		route.mu.Unlock()
+		return nil, fmt.Errorf("mocktest: route %s has no response defined", route)
+	}
+
+	if n >= len(route.responses) {
+		n = len(route.responses) - 1
+	}
+	f := route.responses[n]
+	route.mu.Unlock()
+
+	return f(req.Request)
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// lookupJSONField walks the decoded JSON value by the dot separated path
+func lookupJSONField(value interface{}, path string) (interface{}, bool) {
+	for _, key := range strings.Split(path, ".") {
+		switch v := value.(type) {
+		case map[string]interface{}:
+			field, ok := v[key]
+			if !ok {
+				return nil, false
+			}
+			value = field
+
+		case []interface{}:
+			i, err := strconv.Atoi(key)
+			if err != nil || i < 0 || i >= len(v) {
+				return nil, false
+			}
+			value = v[i]
+
+		default:
+			return nil, false
+		}
+	}
+
+	return value, true
+}
+
+// normalizeJSON converts the value to the generic JSON value, so that it can be compared with the decoded body
+func normalizeJSON(value interface{}) interface{} {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return value
+	}
+
+	var normalized interface{}
+	if err := json.Unmarshal(data, &normalized); err != nil {
+		return value
+	}
+
+	return normalized
+}
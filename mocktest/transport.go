@@ -0,0 +1,208 @@
+// Package mocktest provides a mock http.RoundTripper for testing the API requests.
+//
+// The routes are defined with a fluent builder, which matches the method, the path with the :slug placeholders,
+// the query parameters, the headers and the JSON body fields:
+//
+//	transport := mocktest.NewTransport(t)
+//	route := transport.POST("/v1/orders/:orderID").
+//		WithHeader("KC-API-KEY", "key").
+//		WithJSONField("side", "buy").
+//		RespondStatus(http.StatusServiceUnavailable).Times(2).
+//		RespondJSON(http.StatusOK, map[string]interface{}{"code": "200000"})
+//
+//	client.HttpClient.Transport = transport
+//
+// The requests that don't match any route are reported when the test ends.
+package mocktest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// RoundTripFunc builds the response for the request
+type RoundTripFunc func(req *http.Request) (*http.Response, error)
+
+// Transport is an http.RoundTripper that dispatches the requests to the defined routes.
+// The routes are matched in the order they are defined.
+type Transport struct {
+	t testing.TB
+
+	mu        sync.Mutex
+	routes    []*Route
+	unmatched []*Request
+}
+
+// NewTransport creates a mock transport, the unmatched requests are reported as test errors when the test ends.
+func NewTransport(t testing.TB) *Transport {
+	transport := &Transport{t: t}
+	t.Cleanup(transport.report)
+	return transport
+}
+
+// Client returns an http.Client that uses the transport
+func (transport *Transport) Client() *http.Client {
+	return &http.Client{Transport: transport}
+}
+
+// On defines a route of the given method and path, the path can contain :slug placeholders like the -url option of requestgen.
+func (transport *Transport) On(method, path string) *Route {
+	route := newRoute(method, path)
+
+	transport.mu.Lock()
+	transport.routes = append(transport.routes, route)
+	transport.mu.Unlock()
+	return route
+}
+
+func (transport *Transport) GET(path string) *Route {
+	return transport.On(http.MethodGet, path)
+}
+
+func (transport *Transport) POST(path string) *Route {
+	return transport.On(http.MethodPost, path)
+}
+
+func (transport *Transport) PUT(path string) *Route {
+	return transport.On(http.MethodPut, path)
+}
+
+func (transport *Transport) DELETE(path string) *Route {
+	return transport.On(http.MethodDelete, path)
+}
+
+// Unmatched returns the requests that don't match any route
+func (transport *Transport) Unmatched() []*Request {
+	transport.mu.Lock()
+	defer transport.mu.Unlock()
+	return append([]*Request(nil), transport.unmatched...)
+}
+
+func (transport *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	request, err := captureRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	transport.mu.Lock()
+	var route *Route
+	for _, r := range transport.routes {
+		if r.match(request) {
+			route = r
+			break
+		}
+	}
+
+	if route == nil {
+		transport.unmatched = append(transport.unmatched, request)
+		transport.mu.Unlock()
+		return nil, fmt.Errorf("mocktest: no route matches %s %s", req.Method, req.URL.String())
+	}
+	transport.mu.Unlock()
+
+	resp, err := route.respond(request)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.Header == nil {
+		resp.Header = http.Header{}
+	}
+
+	if resp.Request == nil {
+		resp.Request = req
+	}
+
+	return resp, nil
+}
+
+func (transport *Transport) report() {
+	unmatched := transport.Unmatched()
+	if len(unmatched) == 0 {
+		return
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "mocktest: %d request(s) didn't match any route:\n", len(unmatched))
+	for _, req := range unmatched {
+		fmt.Fprintf(&sb, "\t%s %s", req.Method, req.URL.String())
+		if len(req.Body) > 0 {
+			fmt.Fprintf(&sb, " body: %s", req.Body)
+		}
+		sb.WriteString("\n")
+	}
+
+	transport.mu.Lock()
+	if len(transport.routes) > 0 {
+		sb.WriteString("defined routes:\n")
+		for _, route := range transport.routes {
+			fmt.Fprintf(&sb, "\t%s\n", route)
+		}
+	}
+	transport.mu.Unlock()
+
+	transport.t.Helper()
+	transport.t.Error(sb.String())
+}
+
+// Request is the captured request, the body is read and restored for the responders.
+type Request struct {
+	*http.Request
+
+	// Body is the content of the request body
+	Body []byte
+
+	// PathParams is the values of the :slug placeholders of the matched route
+	PathParams map[string]string
+}
+
+func captureRequest(req *http.Request) (*Request, error) {
+	request := &Request{Request: req}
+	if req.Body == nil || req.Body == http.NoBody {
+		return request, nil
+	}
+
+	body, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	request.Body = body
+	return request, nil
+}
+
+// BuildResponse builds a response with the given status code and body
+func BuildResponse(code int, payload []byte) *http.Response {
+	return &http.Response{
+		StatusCode:    code,
+		Status:        fmt.Sprintf("%d %s", code, http.StatusText(code)),
+		Header:        http.Header{},
+		Body:          io.NopCloser(bytes.NewBuffer(payload)),
+		ContentLength: int64(len(payload)),
+	}
+}
+
+// BuildResponseString builds a response with the given status code and body
+func BuildResponseString(code int, payload string) *http.Response {
+	return BuildResponse(code, []byte(payload))
+}
+
+// BuildResponseJson builds a JSON response with the given status code and payload
+func BuildResponseJson(code int, payload interface{}) *http.Response {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return BuildResponseString(http.StatusInternalServerError, `{"error": "mocktest: json.Marshal() error"}`)
+	}
+
+	resp := BuildResponse(code, data)
+	resp.Header.Set("Content-Type", "application/json")
+	return resp
+}
@@ -0,0 +1,120 @@
+package mocktest
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTransport_Route(t *testing.T) {
+	transport := NewTransport(t)
+	client := transport.Client()
+
+	order := transport.POST("/v1/orders/:orderID/cancel").
+		WithQuery("symbol", "BTC-USDT").
+		WithHeader("KC-API-KEY", "key").
+		WithJSONField("side", "buy").
+		WithJSONField("params.size", 1).
+		RespondJSON(http.StatusOK, map[string]interface{}{"code": "200000"})
+
+	other := transport.POST("/v1/orders/:orderID/cancel").
+		RespondStatus(http.StatusBadRequest)
+
+	req, _ := http.NewRequest("POST", "https://api.example.com/v1/orders/123/cancel?symbol=BTC-USDT&type=limit",
+		strings.NewReader(`{"side":"buy","params":{"size":1}}`))
+	req.Header.Set("KC-API-KEY", "key")
+
+	resp, err := client.Do(req)
+	if assert.NoError(t, err) {
+		body, _ := io.ReadAll(resp.Body)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "application/json", resp.Header.Get("Content-Type"))
+		assert.JSONEq(t, `{"code":"200000"}`, string(body))
+	}
+
+	req, _ = http.NewRequest("POST", "https://api.example.com/v1/orders/456/cancel?symbol=BTC-USDT",
+		strings.NewReader(`{"side":"sell","params":{"size":1}}`))
+	req.Header.Set("KC-API-KEY", "key")
+
+	resp, err = client.Do(req)
+	if assert.NoError(t, err) {
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	}
+
+	assert.Equal(t, 1, order.Calls())
+	assert.Equal(t, 1, other.Calls())
+
+	captured := order.LastRequest()
+	if assert.NotNil(t, captured) {
+		assert.Equal(t, map[string]string{"orderID": "123"}, captured.PathParams)
+		assert.Equal(t, `{"side":"buy","params":{"size":1}}`, string(captured.Body))
+	}
+	assert.Equal(t, "456", other.Requests()[0].PathParams["orderID"])
+}
+
+func TestRoute_Sequence(t *testing.T) {
+	transport := NewTransport(t)
+	client := transport.Client()
+
+	errConnReset := errors.New("connection reset")
+	route := transport.GET("/v1/ping").
+		RespondError(errConnReset).
+		RespondStatus(http.StatusServiceUnavailable).Times(2).
+		RespondString(http.StatusOK, "pong")
+
+	_, err := client.Get("https://api.example.com/v1/ping")
+	assert.True(t, errors.Is(err, errConnReset))
+
+	var codes []int
+	for i := 0; i < 4; i++ {
+		resp, err := client.Get("https://api.example.com/v1/ping")
+		if assert.NoError(t, err) {
+			codes = append(codes, resp.StatusCode)
+		}
+	}
+
+	assert.Equal(t, []int{503, 503, 200, 200}, codes)
+	assert.Equal(t, 5, route.Calls())
+}
+
+type recordingTB struct {
+	testing.TB
+
+	errors   []string
+	cleanups []func()
+}
+
+func (r *recordingTB) Helper() {}
+
+func (r *recordingTB) Error(args ...interface{}) {
+	for _, arg := range args {
+		r.errors = append(r.errors, arg.(string))
+	}
+}
+
+func (r *recordingTB) Cleanup(f func()) {
+	r.cleanups = append(r.cleanups, f)
+}
+
+func TestTransport_UnmatchedReport(t *testing.T) {
+	tb := &recordingTB{TB: t}
+	transport := NewTransport(tb)
+	transport.GET("/v1/ping").RespondStatus(http.StatusOK)
+
+	_, err := transport.Client().Post("https://api.example.com/v1/orders", "application/json", strings.NewReader(`{"side":"buy"}`))
+	assert.Error(t, err)
+	assert.Len(t, transport.Unmatched(), 1)
+
+	for _, f := range tb.cleanups {
+		f()
+	}
+
+	if assert.Len(t, tb.errors, 1) {
+		assert.Contains(t, tb.errors[0], `POST https://api.example.com/v1/orders body: {"side":"buy"}`)
+		assert.Contains(t, tb.errors[0], "GET /v1/ping")
+	}
+}
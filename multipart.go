@@ -0,0 +1,96 @@
+package requestgen
+
+import (
+	"io"
+	"mime/multipart"
+	"net/url"
+	"sort"
+	"sync"
+)
+
+// MultipartFile is a file part of the multipart/form-data request body
+type MultipartFile struct {
+	// FieldName is the form field name of the file part
+	FieldName string
+
+	// FileName is the file name sent with the file part
+	FileName string
+
+	Reader io.Reader
+}
+
+// MultipartPayload is the payload of the multipart/form-data request body.
+// BaseAPIClient.NewRequest streams the file parts after the ordinary fields without buffering them,
+// hence the request body can not be rebuilt for retries.
+type MultipartPayload struct {
+	Fields url.Values
+	Files  []MultipartFile
+}
+
+// multipartBody writes the multipart payload through a pipe, the writer starts on the first read
+// so that no goroutine is left behind if the request is never sent.
+type multipartBody struct {
+	payload *MultipartPayload
+	writer  *multipart.Writer
+	reader  *io.PipeReader
+	pw      *io.PipeWriter
+	once    sync.Once
+}
+
+func newMultipartBody(payload *MultipartPayload) *multipartBody {
+	pr, pw := io.Pipe()
+	return &multipartBody{
+		payload: payload,
+		writer:  multipart.NewWriter(pw),
+		reader:  pr,
+		pw:      pw,
+	}
+}
+
+// ContentType returns the multipart/form-data content type with the boundary
+func (b *multipartBody) ContentType() string {
+	return b.writer.FormDataContentType()
+}
+
+func (b *multipartBody) Read(p []byte) (int, error) {
+	b.once.Do(func() {
+		go func() {
+			b.pw.CloseWithError(b.write())
+		}()
+	})
+
+	return b.reader.Read(p)
+}
+
+func (b *multipartBody) Close() error {
+	return b.reader.Close()
+}
+
+func (b *multipartBody) write() error {
+	keys := make([]string, 0, len(b.payload.Fields))
+	for k := range b.payload.Fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		for _, v := range b.payload.Fields[k] {
+			if err := b.writer.WriteField(k, v); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, file := range b.payload.Files {
+		part, err := b.writer.CreateFormFile(file.FieldName, file.FileName)
+		if err != nil {
+			return err
+		}
+
+		if _, err := io.Copy(part, file.Reader); err != nil {
+			return err
+		}
+	}
+
+	return b.writer.Close()
+}
@@ -0,0 +1,124 @@
+package requestgen
+
+import (
+	"net/http"
+	"time"
+)
+
+// Observation is the metrics of a request sent by BaseAPIClient
+type Observation struct {
+	// Name is the endpoint name, which is the request type name of the generated request
+	Name string
+
+	// Method is the HTTP method
+	Method string
+
+	// PathTemplate is the request path before the slugs are substituted, e.g. /v1/orders/:orderID.
+	// The path of the request URL is used if the request is not sent by the generated Do method.
+	PathTemplate string
+
+	// StatusCode is the status code of the response, zero if there is no response
+	StatusCode int
+
+	// Duration is the time from sending the request to reading the response body
+	Duration time.Duration
+
+	// RequestBytes is the size of the request body, -1 if it's unknown
+	RequestBytes int64
+
+	// ResponseBytes is the size of the response body, -1 if it's unknown
+	ResponseBytes int64
+
+	// IdempotencyKey is the idempotency key of the generated request, it's the same for all the retry attempts
+	IdempotencyKey string
+
+	// RateLimitWait is the time spent waiting for the rate limiter in the generated Do method
+	RateLimitWait time.Duration
+
+	// Err is the error of the request
+	Err error
+}
+
+// Endpoint returns the name of the endpoint, or the method and the path template if the name is empty
+func (o *Observation) Endpoint() string {
+	return endpointName(o.Name, o.Method, o.PathTemplate)
+}
+
+func endpointName(name, method, pathTemplate string) string {
+	if name != "" {
+		return name
+	}
+
+	return method + " " + pathTemplate
+}
+
+// Observer receives the metrics of the requests, it should be safe for concurrent use.
+type Observer interface {
+	// ObserveRateLimitWait is called by the generated Do method after waiting for the rate limiter,
+	// err is not nil if the wait failed, e.g. the context is canceled.
+	ObserveRateLimitWait(meta *RequestMeta, wait time.Duration, err error)
+
+	// ObserveRequest is called by BaseAPIClient after each HTTP request, including each retry attempt.
+	ObserveRequest(observation *Observation)
+}
+
+// ObservableAPIClient is the API client that provides an observer to the generated requests
+type ObservableAPIClient interface {
+	GetObserver() Observer
+}
+
+// GetObserver returns the observer of the client
+func (c *BaseAPIClient) GetObserver() Observer {
+	return c.Observer
+}
+
+// ObserveRateLimitWait adds the rate limiter wait time to the request meta,
+// and reports it to the observer of the client if the client implements ObservableAPIClient.
+func ObserveRateLimitWait(client interface{}, meta *RequestMeta, wait time.Duration, err error) {
+	if meta != nil {
+		meta.RateLimitWait += wait
+	}
+
+	observable, ok := client.(ObservableAPIClient)
+	if !ok {
+		return
+	}
+
+	if observer := observable.GetObserver(); observer != nil {
+		observer.ObserveRateLimitWait(meta, wait, err)
+	}
+}
+
+// observe reports the request to the observer, responseBytes is -1 if the size of the response body is unknown
+func (c *BaseAPIClient) observe(req *http.Request, statusCode int, responseBytes int64, start time.Time, err error) {
+	if c.Observer == nil {
+		return
+	}
+
+	observation := &Observation{
+		Method:        req.Method,
+		PathTemplate:  req.URL.Path,
+		StatusCode:    statusCode,
+		Duration:      time.Since(start),
+		RequestBytes:  req.ContentLength,
+		ResponseBytes: responseBytes,
+		Err:           err,
+	}
+
+	if req.Body == nil || req.Body == http.NoBody {
+		observation.RequestBytes = 0
+	} else if req.ContentLength == 0 {
+		observation.RequestBytes = -1
+	}
+
+	if meta := RequestMetaFromContext(req.Context()); meta != nil {
+		observation.Name = meta.Name
+		observation.IdempotencyKey = meta.IdempotencyKey
+		observation.RateLimitWait = meta.RateLimitWait
+		if meta.PathTemplate != "" {
+			observation.PathTemplate = meta.PathTemplate
+		}
+	}
+
+	c.Observer.ObserveRequest(observation)
+}
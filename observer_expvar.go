@@ -0,0 +1,86 @@
+package requestgen
+
+import (
+	"expvar"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// ExpvarObserver is an Observer that publishes the metrics of each endpoint with expvar.
+// The counters of an endpoint are:
+//
+//	requests, errors, status_2xx (by the status class), duration_ns, request_bytes, response_bytes,
+//	rate_limit_waits, rate_limit_wait_ns and rate_limit_errors
+//
+// The durations are the sums in nanoseconds, the average is the sum divided by the count.
+type ExpvarObserver struct {
+	endpoints *expvar.Map
+
+	mu sync.Mutex
+}
+
+// NewExpvarObserver creates an ExpvarObserver and publishes the metrics with the given name,
+// it panics if the name is already published, like expvar.Publish.
+func NewExpvarObserver(name string) *ExpvarObserver {
+	return &ExpvarObserver{endpoints: expvar.NewMap(name)}
+}
+
+// Endpoint returns the metrics of the endpoint, nil is returned if the endpoint is not observed yet.
+func (o *ExpvarObserver) Endpoint(name string) *expvar.Map {
+	m, _ := o.endpoints.Get(name).(*expvar.Map)
+	return m
+}
+
+func (o *ExpvarObserver) endpoint(name string) *expvar.Map {
+	if m := o.Endpoint(name); m != nil {
+		return m
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if m := o.Endpoint(name); m != nil {
+		return m
+	}
+
+	m := new(expvar.Map)
+	o.endpoints.Set(name, m)
+	return m
+}
+
+func (o *ExpvarObserver) ObserveRateLimitWait(meta *RequestMeta, wait time.Duration, err error) {
+	name := ""
+	if meta != nil {
+		name = endpointName(meta.Name, meta.Method, meta.PathTemplate)
+	}
+
+	m := o.endpoint(name)
+	m.Add("rate_limit_waits", 1)
+	m.Add("rate_limit_wait_ns", int64(wait))
+	if err != nil {
+		m.Add("rate_limit_errors", 1)
+	}
+}
+
+func (o *ExpvarObserver) ObserveRequest(observation *Observation) {
+	m := o.endpoint(observation.Endpoint())
+	m.Add("requests", 1)
+	m.Add("duration_ns", int64(observation.Duration))
+
+	if observation.Err != nil {
+		m.Add("errors", 1)
+	}
+
+	if observation.StatusCode > 0 {
+		m.Add("status_"+strconv.Itoa(observation.StatusCode/100)+"xx", 1)
+	}
+
+	if observation.RequestBytes > 0 {
+		m.Add("request_bytes", observation.RequestBytes)
+	}
+
+	if observation.ResponseBytes > 0 {
+		m.Add("response_bytes", observation.ResponseBytes)
+	}
+}
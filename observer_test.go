@@ -0,0 +1,101 @@
+package requestgen
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testObserver struct {
+	mu           sync.Mutex
+	waits        []time.Duration
+	observations []*Observation
+}
+
+func (o *testObserver) ObserveRateLimitWait(meta *RequestMeta, wait time.Duration, err error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.waits = append(o.waits, wait)
+}
+
+func (o *testObserver) ObserveRequest(observation *Observation) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.observations = append(o.observations, observation)
+}
+
+func TestBaseAPIClient_Observer(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/v1/orders/missing" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		w.Write([]byte(`{"orderId":"123"}`))
+	})
+
+	observer := &testObserver{}
+	client.Observer = observer
+
+	meta := &RequestMeta{Name: "GetOrderRequest", Method: "GET", PathTemplate: "/api/v1/orders/:orderID"}
+	ctx := WithRequestMeta(context.Background(), meta)
+	ObserveRateLimitWait(client, meta, 5*time.Millisecond, nil)
+
+	req, err := client.NewRequest(ctx, "GET", "/api/v1/orders/123", nil, nil)
+	assert.NoError(t, err)
+	_, err = client.SendRequest(req)
+	assert.NoError(t, err)
+
+	req, err = client.NewRequest(context.Background(), "POST", "/api/v1/orders/missing", nil, map[string]string{"a": "b"})
+	assert.NoError(t, err)
+	_, err = client.SendRequest(req)
+	assert.Error(t, err)
+
+	assert.Equal(t, []time.Duration{5 * time.Millisecond}, observer.waits)
+	assert.Equal(t, 5*time.Millisecond, meta.RateLimitWait)
+
+	if assert.Len(t, observer.observations, 2) {
+		o := observer.observations[0]
+		assert.Equal(t, "GetOrderRequest", o.Endpoint())
+		assert.Equal(t, "/api/v1/orders/:orderID", o.PathTemplate)
+		assert.Equal(t, http.StatusOK, o.StatusCode)
+		assert.Equal(t, int64(0), o.RequestBytes)
+		assert.Equal(t, int64(len(`{"orderId":"123"}`)), o.ResponseBytes)
+		assert.Equal(t, 5*time.Millisecond, o.RateLimitWait)
+		assert.True(t, o.Duration > 0)
+		assert.NoError(t, o.Err)
+
+		o = observer.observations[1]
+		assert.Equal(t, "POST /api/v1/orders/missing", o.Endpoint())
+		assert.Equal(t, http.StatusNotFound, o.StatusCode)
+		assert.Equal(t, int64(len(`{"a":"b"}`)), o.RequestBytes)
+		assert.Error(t, o.Err)
+	}
+}
+
+func TestExpvarObserver(t *testing.T) {
+	observer := NewExpvarObserver("requestgen_test")
+
+	meta := &RequestMeta{Name: "GetTickerRequest"}
+	observer.ObserveRateLimitWait(meta, time.Second, nil)
+	observer.ObserveRequest(&Observation{Name: "GetTickerRequest", StatusCode: 200, Duration: time.Second, ResponseBytes: 10})
+	observer.ObserveRequest(&Observation{Name: "GetTickerRequest", StatusCode: 503, Duration: time.Second, ResponseBytes: 5, Err: &ErrResponse{}})
+
+	m := observer.Endpoint("GetTickerRequest")
+	if assert.NotNil(t, m) {
+		assert.Equal(t, "2", m.Get("requests").String())
+		assert.Equal(t, "1", m.Get("errors").String())
+		assert.Equal(t, "1", m.Get("status_2xx").String())
+		assert.Equal(t, "1", m.Get("status_5xx").String())
+		assert.Equal(t, "15", m.Get("response_bytes").String())
+		assert.Equal(t, "2000000000", m.Get("duration_ns").String())
+		assert.Equal(t, "1", m.Get("rate_limit_waits").String())
+		assert.Equal(t, "1000000000", m.Get("rate_limit_wait_ns").String())
+	}
+
+	assert.Nil(t, observer.Endpoint("PlaceOrderRequest"))
+}
@@ -0,0 +1,244 @@
+package requestgen
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// AdaptiveRateLimiter throttles the requests by the live rate limit budget published in the response headers,
+// e.g. X-RateLimit-Remaining, X-MBX-USED-WEIGHT-1M and Retry-After.
+//
+// When the remaining budget drops to LowWatermark, the following requests are spread evenly until the budget resets,
+// and when the budget is used up, the requests wait for the reset. The reset time is read from ResetHeader,
+// or estimated by Window. The budget is not throttled if neither of them is set, but Retry-After is always honored.
+//
+// For example, the Binance request weight is:
+//
+//	limiter := &requestgen.AdaptiveRateLimiter{
+//		UsedHeader:   "X-MBX-USED-WEIGHT-1M",
+//		Limit:        1200,
+//		Window:       time.Minute,
+//		LowWatermark: 100,
+//	}
+type AdaptiveRateLimiter struct {
+	// RemainingHeader is the header of the remaining budget, e.g. X-RateLimit-Remaining
+	RemainingHeader string
+
+	// UsedHeader is the header of the used budget, e.g. X-MBX-USED-WEIGHT-1M,
+	// it's used with the limit when RemainingHeader is empty or missing.
+	UsedHeader string
+
+	// LimitHeader is the header of the total budget, e.g. X-RateLimit-Limit, Limit is used if it's missing.
+	LimitHeader string
+	Limit       int64
+
+	// ResetHeader is the header of the seconds until the budget resets, e.g. X-RateLimit-Reset.
+	// The values that look like unix timestamps are treated as the reset time.
+	ResetHeader string
+
+	// Window is the fixed window of the budget, the budget is assumed to reset at the end of the current window
+	// when ResetHeader is empty or missing.
+	Window time.Duration
+
+	// LowWatermark is the remaining budget that starts the throttling
+	LowWatermark int64
+
+	mu           sync.Mutex
+	known        bool
+	remaining    int64
+	resetAt      time.Time
+	next         time.Time
+	blockedUntil time.Time
+}
+
+// NewAdaptiveRateLimiter creates an AdaptiveRateLimiter that reads the remaining budget and the reset seconds from the given headers
+func NewAdaptiveRateLimiter(remainingHeader, resetHeader string, lowWatermark int64) *AdaptiveRateLimiter {
+	return &AdaptiveRateLimiter{
+		RemainingHeader: remainingHeader,
+		ResetHeader:     resetHeader,
+		LowWatermark:    lowWatermark,
+	}
+}
+
+// Remaining returns the remaining budget, false is returned if it's unknown, e.g. the budget has been reset.
+func (l *AdaptiveRateLimiter) Remaining() (int64, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.expire(time.Now())
+	return l.remaining, l.known
+}
+
+// Wait blocks until the request can be sent, or the context is done.
+// Each call takes one unit of the remaining budget until the next Update.
+func (l *AdaptiveRateLimiter) Wait(ctx context.Context) error {
+	return l.WaitN(ctx, 1)
+}
+
+// WaitN is like Wait, but the request takes n units of the remaining budget, e.g. the weight of the request.
+// Like rate.Limiter, it does not wait if n is zero.
+func (l *AdaptiveRateLimiter) WaitN(ctx context.Context, n int) error {
+	if n <= 0 {
+		return ctx.Err()
+	}
+
+	return sleepContext(ctx, l.reserve(time.Now(), int64(n)))
+}
+
+func (l *AdaptiveRateLimiter) reserve(now time.Time, n int64) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	at := now
+	if at.Before(l.blockedUntil) {
+		at = l.blockedUntil
+	}
+
+	l.expire(at)
+	if !l.known {
+		return at.Sub(now)
+	}
+
+	if l.remaining < n {
+		at = l.resetAt
+	} else if l.remaining <= l.LowWatermark {
+		if at.Before(l.next) {
+			at = l.next
+		}
+
+		l.next = at.Add(time.Duration(int64(l.resetAt.Sub(at)) * n / (l.remaining + n)))
+	}
+
+	l.remaining -= n
+	return at.Sub(now)
+}
+
+// expire forgets the budget after it's reset, the budget is unknown until the next Update
+func (l *AdaptiveRateLimiter) expire(now time.Time) {
+	if l.known && !now.Before(l.resetAt) {
+		l.known = false
+		l.next = time.Time{}
+	}
+}
+
+// Update reads the budget from the response headers
+func (l *AdaptiveRateLimiter) Update(header http.Header) {
+	l.update(header, time.Now())
+}
+
+func (l *AdaptiveRateLimiter) update(header http.Header, now time.Time) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if delay, ok := parseRetryAfter(header.Get("Retry-After")); ok && now.Add(delay).After(l.blockedUntil) {
+		l.blockedUntil = now.Add(delay)
+	}
+
+	remaining, ok := l.parseRemaining(header)
+	if !ok {
+		return
+	}
+
+	resetAt, ok := l.parseReset(header, now)
+	if !ok {
+		return
+	}
+
+	if !resetAt.Equal(l.resetAt) {
+		l.next = time.Time{}
+	}
+
+	l.known = true
+	l.remaining = remaining
+	l.resetAt = resetAt
+}
+
+func (l *AdaptiveRateLimiter) parseRemaining(header http.Header) (int64, bool) {
+	if l.RemainingHeader != "" {
+		if remaining, err := strconv.ParseInt(strings.TrimSpace(header.Get(l.RemainingHeader)), 10, 64); err == nil {
+			return remaining, true
+		}
+	}
+
+	if l.UsedHeader == "" {
+		return 0, false
+	}
+
+	used, err := strconv.ParseInt(strings.TrimSpace(header.Get(l.UsedHeader)), 10, 64)
+	if err != nil {
+		return 0, false
+	}
+
+	limit := l.Limit
+	if l.LimitHeader != "" {
+		if v, err := strconv.ParseInt(strings.TrimSpace(header.Get(l.LimitHeader)), 10, 64); err == nil {
+			limit = v
+		}
+	}
+
+	if limit <= 0 {
+		return 0, false
+	}
+
+	return limit - used, true
+}
+
+// unixTimestampThreshold separates the reset seconds from the reset timestamps, it's about a year in seconds
+const unixTimestampThreshold = 365 * 24 * 60 * 60
+
+func (l *AdaptiveRateLimiter) parseReset(header http.Header, now time.Time) (time.Time, bool) {
+	if l.ResetHeader != "" {
+		if seconds, err := strconv.ParseFloat(strings.TrimSpace(header.Get(l.ResetHeader)), 64); err == nil {
+			if seconds > unixTimestampThreshold {
+				return time.Unix(0, int64(seconds*float64(time.Second))), true
+			}
+
+			return now.Add(time.Duration(seconds * float64(time.Second))), true
+		}
+	}
+
+	if l.Window > 0 {
+		return now.Truncate(l.Window).Add(l.Window), true
+	}
+
+	return time.Time{}, false
+}
+
+// AdaptiveRateLimitedAPIClient is implemented by the API clients that provide an AdaptiveRateLimiter
+type AdaptiveRateLimitedAPIClient interface {
+	GetAdaptiveRateLimiter() *AdaptiveRateLimiter
+}
+
+// WaitAdaptiveRateLimit waits for the adaptive rate limiter of the client with the weight of the request meta,
+// the wait time is recorded like ObserveRateLimitWait.
+// It does nothing if the client does not implement AdaptiveRateLimitedAPIClient or has no adaptive rate limiter.
+func WaitAdaptiveRateLimit(ctx context.Context, client interface{}, meta *RequestMeta) error {
+	limited, ok := client.(AdaptiveRateLimitedAPIClient)
+	if !ok {
+		return nil
+	}
+
+	limiter := limited.GetAdaptiveRateLimiter()
+	if limiter == nil {
+		return nil
+	}
+
+	weight := 1
+	if meta != nil && meta.Weight > 0 {
+		weight = meta.Weight
+	}
+
+	waitStart := time.Now()
+	err := limiter.WaitN(ctx, weight)
+	ObserveRateLimitWait(client, meta, time.Since(waitStart), err)
+	return err
+}
+
+// GetAdaptiveRateLimiter returns the adaptive rate limiter of the client
+func (c *BaseAPIClient) GetAdaptiveRateLimiter() *AdaptiveRateLimiter {
+	return c.AdaptiveRateLimiter
+}
@@ -0,0 +1,141 @@
+package requestgen
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAdaptiveRateLimiter_Throttle(t *testing.T) {
+	now := time.Now()
+	limiter := NewAdaptiveRateLimiter("X-RateLimit-Remaining", "X-RateLimit-Reset", 3)
+
+	// plenty of budget
+	limiter.update(http.Header{"X-Ratelimit-Remaining": {"100"}, "X-Ratelimit-Reset": {"60"}}, now)
+	assert.Equal(t, time.Duration(0), limiter.reserve(now, 1))
+
+	remaining, ok := limiter.Remaining()
+	assert.True(t, ok)
+	assert.Equal(t, int64(99), remaining)
+
+	// the low budget is spread until the reset
+	limiter.update(http.Header{"X-Ratelimit-Remaining": {"3"}, "X-Ratelimit-Reset": {"40"}}, now)
+	assert.Equal(t, time.Duration(0), limiter.reserve(now, 1))
+	assert.Equal(t, 10*time.Second, limiter.reserve(now, 1))
+	assert.Equal(t, 20*time.Second, limiter.reserve(now, 1))
+
+	// the used up budget waits for the reset
+	assert.Equal(t, 40*time.Second, limiter.reserve(now, 1))
+
+	// the budget is unknown after the reset
+	assert.Equal(t, time.Duration(0), limiter.reserve(now.Add(time.Minute), 1))
+}
+
+func TestAdaptiveRateLimiter_WaitZero(t *testing.T) {
+	limiter := NewAdaptiveRateLimiter("X-RateLimit-Remaining", "X-RateLimit-Reset", 3)
+	limiter.Update(http.Header{"X-Ratelimit-Remaining": {"0"}, "X-Ratelimit-Reset": {"60"}})
+
+	// a zero weight request takes no budget and does not wait
+	assert.NoError(t, limiter.WaitN(context.Background(), 0))
+
+	remaining, ok := limiter.Remaining()
+	assert.True(t, ok)
+	assert.Equal(t, int64(0), remaining)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.Equal(t, context.Canceled, limiter.WaitN(ctx, 0))
+}
+
+func TestAdaptiveRateLimiter_UsedWeight(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 30, 0, time.UTC)
+	limiter := &AdaptiveRateLimiter{
+		UsedHeader: "X-MBX-USED-WEIGHT-1M",
+		Limit:      1200,
+		Window:     time.Minute,
+	}
+
+	limiter.update(http.Header{"X-Mbx-Used-Weight-1m": {"1200"}}, now)
+	assert.Equal(t, 30*time.Second, limiter.reserve(now, 1))
+
+	// the heavy requests take their weight from the budget
+	limiter.update(http.Header{"X-Mbx-Used-Weight-1m": {"1100"}}, now)
+	assert.Equal(t, time.Duration(0), limiter.reserve(now, 50))
+	assert.Equal(t, time.Duration(0), limiter.reserve(now, 50))
+	assert.Equal(t, 30*time.Second, limiter.reserve(now, 1))
+}
+
+func TestAdaptiveRateLimiter_RetryAfter(t *testing.T) {
+	now := time.Now()
+	limiter := NewAdaptiveRateLimiter("X-RateLimit-Remaining", "X-RateLimit-Reset", 0)
+
+	// the reset timestamp is supported
+	limiter.update(http.Header{
+		"Retry-After":           {"5"},
+		"X-Ratelimit-Remaining": {"10"},
+		"X-Ratelimit-Reset":     {"1704067200"},
+	}, now)
+	assert.InDelta(t, float64(5*time.Second), float64(limiter.reserve(now, 1)), float64(time.Second))
+
+	// the budget is not known without the reset time
+	limiter = NewAdaptiveRateLimiter("X-RateLimit-Remaining", "", 10)
+	limiter.update(http.Header{"X-Ratelimit-Remaining": {"0"}}, now)
+	_, ok := limiter.Remaining()
+	assert.False(t, ok)
+}
+
+func TestWaitAdaptiveRateLimit(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-RateLimit-Remaining", "0")
+		w.Header().Set("X-RateLimit-Reset", "0.2")
+		w.Write([]byte(`{}`))
+	})
+	client.AdaptiveRateLimiter = NewAdaptiveRateLimiter("X-RateLimit-Remaining", "X-RateLimit-Reset", 1)
+
+	meta := &RequestMeta{}
+	ctx := WithRequestMeta(context.Background(), meta)
+	assert.NoError(t, WaitAdaptiveRateLimit(ctx, client, meta))
+
+	req, err := client.NewRequest(ctx, "GET", "/v1/orders", nil, nil)
+	assert.NoError(t, err)
+
+	_, err = client.SendRequest(req)
+	assert.NoError(t, err)
+
+	remaining, ok := client.AdaptiveRateLimiter.Remaining()
+	assert.True(t, ok)
+	assert.Equal(t, int64(0), remaining)
+
+	assert.NoError(t, WaitAdaptiveRateLimit(ctx, client, meta))
+	assert.True(t, meta.RateLimitWait > 100*time.Millisecond)
+
+	// the wait is canceled with the context
+	client.AdaptiveRateLimiter.Update(http.Header{"X-Ratelimit-Remaining": {"0"}, "X-Ratelimit-Reset": {"10"}})
+	cancelCtx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	assert.Equal(t, context.DeadlineExceeded, WaitAdaptiveRateLimit(cancelCtx, client, meta))
+}
+
+func TestAdaptiveRateLimiter_SendStreamRequest(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-RateLimit-Remaining", "7")
+		w.Header().Set("X-RateLimit-Reset", "60")
+		w.Write([]byte(`[]`))
+	})
+	client.AdaptiveRateLimiter = NewAdaptiveRateLimiter("X-RateLimit-Remaining", "X-RateLimit-Reset", 1)
+
+	req, err := client.NewRequest(context.Background(), "GET", "/v1/market/histories", nil, nil)
+	assert.NoError(t, err)
+
+	resp, err := client.SendStreamRequest(req)
+	if assert.NoError(t, err) {
+		resp.Close()
+	}
+
+	remaining, ok := client.AdaptiveRateLimiter.Remaining()
+	assert.True(t, ok)
+	assert.Equal(t, int64(7), remaining)
+}
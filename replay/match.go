@@ -0,0 +1,65 @@
+package replay
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"reflect"
+)
+
+// matchRequest checks if the recorded request matches the method, the path, the query and the body of the given request
+func matchRequest(recorded *Request, req *http.Request, body []byte) bool {
+	if recorded.Method != req.Method {
+		return false
+	}
+
+	recordedURL, err := url.Parse(recorded.URL)
+	if err != nil {
+		return false
+	}
+
+	if recordedURL.Path != req.URL.Path || !matchQuery(recordedURL.RawQuery, req.URL.RawQuery) {
+		return false
+	}
+
+	recordedBody, err := recorded.Body.Bytes()
+	if err != nil {
+		return false
+	}
+
+	return matchBody(recordedBody, body)
+}
+
+// matchQuery compares the queries regardless of the parameter order
+func matchQuery(a, b string) bool {
+	if a == b {
+		return true
+	}
+
+	queryA, err := url.ParseQuery(a)
+	if err != nil {
+		return false
+	}
+
+	queryB, err := url.ParseQuery(b)
+	if err != nil {
+		return false
+	}
+
+	return reflect.DeepEqual(queryA, queryB)
+}
+
+// matchBody compares the bodies, JSON bodies are compared by their values, so the key order and the spaces don't matter.
+func matchBody(a, b []byte) bool {
+	if bytes.Equal(a, b) {
+		return true
+	}
+
+	var valueA, valueB interface{}
+	if json.Unmarshal(a, &valueA) != nil || json.Unmarshal(b, &valueB) != nil {
+		return false
+	}
+
+	return reflect.DeepEqual(valueA, valueB)
+}
@@ -0,0 +1,298 @@
+// Package replay provides an http.RoundTripper that records the HTTP exchanges to a cassette file,
+// and replays them later without network access, so that the API requests can be tested deterministically.
+package replay
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"unicode/utf8"
+)
+
+// Mode is the working mode of the Transport
+type Mode int
+
+const (
+	// ModeReplay replays the recorded interactions from the cassette, no request is sent to the server.
+	ModeReplay Mode = iota
+
+	// ModeRecord sends the requests to the server and records the interactions into the cassette.
+	ModeRecord
+)
+
+// RedactedValue replaces the values of the secret headers in the cassette
+const RedactedValue = "[REDACTED]"
+
+// DefaultSecretHeaders are the headers that are always scrubbed from the recorded interactions
+var DefaultSecretHeaders = []string{"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie"}
+
+// ErrInteractionNotFound is returned in the replay mode when no recorded interaction matches the request
+type ErrInteractionNotFound struct {
+	Method string
+	URL    string
+}
+
+func (e *ErrInteractionNotFound) Error() string {
+	return fmt.Sprintf("replay: no recorded interaction matches %s %s", e.Method, e.URL)
+}
+
+// Body is the recorded body, binary content is stored in base64
+type Body struct {
+	Data     string `json:"data,omitempty"`
+	Encoding string `json:"encoding,omitempty"`
+}
+
+func newBody(data []byte) Body {
+	if utf8.Valid(data) {
+		return Body{Data: string(data)}
+	}
+
+	return Body{Data: base64.StdEncoding.EncodeToString(data), Encoding: "base64"}
+}
+
+// Bytes returns the decoded body content
+func (b Body) Bytes() ([]byte, error) {
+	if b.Encoding == "base64" {
+		return base64.StdEncoding.DecodeString(b.Data)
+	}
+
+	return []byte(b.Data), nil
+}
+
+// Request is the recorded request
+type Request struct {
+	Method string      `json:"method"`
+	URL    string      `json:"url"`
+	Header http.Header `json:"header,omitempty"`
+	Body   Body        `json:"body"`
+}
+
+// Response is the recorded response
+type Response struct {
+	StatusCode int         `json:"statusCode"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       Body        `json:"body"`
+}
+
+// Interaction is a recorded request and response pair
+type Interaction struct {
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+}
+
+// Cassette is the file format of the recorded interactions
+type Cassette struct {
+	Interactions []*Interaction `json:"interactions"`
+}
+
+// LoadCassette loads the cassette from the given file
+func LoadCassette(path string) (*Cassette, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var cassette Cassette
+	if err := json.Unmarshal(data, &cassette); err != nil {
+		return nil, fmt.Errorf("replay: unable to decode cassette %s: %w", path, err)
+	}
+
+	return &cassette, nil
+}
+
+// Save writes the cassette to the given file, the parent directories are created if they don't exist.
+func (c *Cassette) Save(path string) error {
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, append(data, '\n'), 0644)
+}
+
+// Transport is an http.RoundTripper that records or replays the HTTP interactions.
+//
+// The requests are matched by the method, the path, the query and the body.
+// Each recorded interaction is replayed once, in the recorded order,
+// hence the same request sent twice replays two different responses.
+type Transport struct {
+	// Mode is the working mode of the transport
+	Mode Mode
+
+	// Path is the path of the cassette file
+	Path string
+
+	// Transport sends the requests in the record mode, http.DefaultTransport is used when it's nil.
+	Transport http.RoundTripper
+
+	// SecretHeaders are the headers to be scrubbed from the cassette, e.g. KC-API-SIGN and KC-API-KEY.
+	// The request headers are not used for matching, so the scrubbed cassette still replays.
+	SecretHeaders []string
+
+	mu       sync.Mutex
+	cassette *Cassette
+	used     []bool
+}
+
+// NewTransport creates a transport with the given cassette file and mode.
+// In the replay mode, the cassette is loaded from the file.
+func NewTransport(path string, mode Mode, secretHeaders ...string) (*Transport, error) {
+	t := &Transport{
+		Mode:          mode,
+		Path:          path,
+		SecretHeaders: append(append([]string{}, DefaultSecretHeaders...), secretHeaders...),
+		cassette:      &Cassette{},
+	}
+
+	if mode == ModeReplay {
+		cassette, err := LoadCassette(path)
+		if err != nil {
+			return nil, err
+		}
+
+		t.cassette = cassette
+		t.used = make([]bool, len(cassette.Interactions))
+	}
+
+	return t, nil
+}
+
+// Cassette returns the recorded or loaded cassette
+func (t *Transport) Cassette() *Cassette {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.cassette
+}
+
+// Save writes the recorded interactions to the cassette file, it does nothing in the replay mode.
+func (t *Transport) Save() error {
+	if t.Mode != ModeRecord {
+		return nil
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.cassette.Save(t.Path)
+}
+
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := readRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if t.Mode == ModeRecord {
+		return t.record(req, body)
+	}
+
+	return t.replay(req, body)
+}
+
+func (t *Transport) record(req *http.Request, body []byte) (*http.Response, error) {
+	transport := t.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	respBody, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+	interaction := &Interaction{
+		Request: Request{
+			Method: req.Method,
+			URL:    req.URL.String(),
+			Header: t.scrub(req.Header),
+			Body:   newBody(body),
+		},
+		Response: Response{
+			StatusCode: resp.StatusCode,
+			Header:     t.scrub(resp.Header),
+			Body:       newBody(respBody),
+		},
+	}
+
+	t.mu.Lock()
+	t.cassette.Interactions = append(t.cassette.Interactions, interaction)
+	t.used = append(t.used, false)
+	t.mu.Unlock()
+
+	return resp, nil
+}
+
+func (t *Transport) replay(req *http.Request, body []byte) (*http.Response, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for i, interaction := range t.cassette.Interactions {
+		if t.used[i] || !matchRequest(&interaction.Request, req, body) {
+			continue
+		}
+
+		respBody, err := interaction.Response.Body.Bytes()
+		if err != nil {
+			return nil, err
+		}
+
+		t.used[i] = true
+		return &http.Response{
+			Status:        strconv.Itoa(interaction.Response.StatusCode) + " " + http.StatusText(interaction.Response.StatusCode),
+			StatusCode:    interaction.Response.StatusCode,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        interaction.Response.Header.Clone(),
+			Body:          io.NopCloser(bytes.NewReader(respBody)),
+			ContentLength: int64(len(respBody)),
+			Request:       req,
+		}, nil
+	}
+
+	return nil, &ErrInteractionNotFound{Method: req.Method, URL: req.URL.String()}
+}
+
+func (t *Transport) scrub(header http.Header) http.Header {
+	header = header.Clone()
+	for _, key := range t.SecretHeaders {
+		if _, ok := header[http.CanonicalHeaderKey(key)]; ok {
+			header.Set(key, RedactedValue)
+		}
+	}
+
+	return header
+}
+
+// readRequestBody reads the request body and restores it for the underlying transport
+func readRequestBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+
+	body, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	return body, nil
+}
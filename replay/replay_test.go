@@ -0,0 +1,113 @@
+package replay
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTransport_RecordAndReplay(t *testing.T) {
+	var calls int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Set-Cookie", "session=secret")
+		w.Write([]byte(`{"path":"` + r.URL.Path + `","body":` + string(body) + `}`))
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "cassettes", "orders.json")
+
+	recorder, err := NewTransport(path, ModeRecord, "KC-API-SIGN", "KC-API-KEY")
+	assert.NoError(t, err)
+
+	client := &http.Client{Transport: recorder}
+
+	req, _ := http.NewRequest("POST", server.URL+"/v1/orders?symbol=BTC-USDT&side=buy", strings.NewReader(`{"size":"1","price":"100"}`))
+	req.Header.Set("KC-API-KEY", "my-key")
+	req.Header.Set("KC-API-SIGN", "my-signature")
+	resp, err := client.Do(req)
+	if assert.NoError(t, err) {
+		body, _ := io.ReadAll(resp.Body)
+		assert.Equal(t, `{"path":"/v1/orders","body":{"size":"1","price":"100"}}`, string(body))
+	}
+
+	assert.NoError(t, recorder.Save())
+
+	data, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.NotContains(t, string(data), "my-key")
+	assert.NotContains(t, string(data), "my-signature")
+	assert.NotContains(t, string(data), "session=secret")
+	assert.Contains(t, string(data), RedactedValue)
+
+	player, err := NewTransport(path, ModeReplay)
+	assert.NoError(t, err)
+
+	client = &http.Client{Transport: player}
+
+	// the query order, the JSON key order and the secret headers don't matter
+	req, _ = http.NewRequest("POST", "https://api.example.com/v1/orders?side=buy&symbol=BTC-USDT", strings.NewReader(`{"price":"100", "size":"1"}`))
+	req.Header.Set("KC-API-SIGN", "another-signature")
+	resp, err = client.Do(req)
+	if assert.NoError(t, err) {
+		body, _ := io.ReadAll(resp.Body)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "application/json", resp.Header.Get("Content-Type"))
+		assert.Equal(t, `{"path":"/v1/orders","body":{"size":"1","price":"100"}}`, string(body))
+	}
+
+	assert.Equal(t, 1, calls)
+
+	// the interaction is already replayed
+	req, _ = http.NewRequest("POST", "https://api.example.com/v1/orders?side=buy&symbol=BTC-USDT", strings.NewReader(`{"price":"100","size":"1"}`))
+	_, err = client.Do(req)
+	var notFound *ErrInteractionNotFound
+	assert.True(t, errors.As(err, &notFound))
+}
+
+func TestTransport_ReplayMismatch(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cassette.json")
+	cassette := &Cassette{Interactions: []*Interaction{
+		{
+			Request:  Request{Method: "GET", URL: "https://api.example.com/v1/ticker?symbol=BTC-USDT"},
+			Response: Response{StatusCode: 200, Body: newBody([]byte(`{"price":"100"}`))},
+		},
+		{
+			Request:  Request{Method: "GET", URL: "https://api.example.com/v1/ticker?symbol=ETH-USDT"},
+			Response: Response{StatusCode: 200, Body: newBody([]byte{0x1f, 0x8b, 0xff})},
+		},
+	}}
+	assert.NoError(t, cassette.Save(path))
+
+	player, err := NewTransport(path, ModeReplay)
+	assert.NoError(t, err)
+
+	for _, u := range []string{
+		"https://api.example.com/v1/ticker?symbol=LTC-USDT",
+		"https://api.example.com/v2/ticker?symbol=BTC-USDT",
+	} {
+		req, _ := http.NewRequest("GET", u, nil)
+		_, err := player.RoundTrip(req)
+		assert.Error(t, err, u)
+	}
+
+	req, _ := http.NewRequest("POST", "https://api.example.com/v1/ticker?symbol=BTC-USDT", nil)
+	_, err = player.RoundTrip(req)
+	assert.Error(t, err)
+
+	req, _ = http.NewRequest("GET", "https://api.example.com/v1/ticker?symbol=ETH-USDT", nil)
+	resp, err := player.RoundTrip(req)
+	if assert.NoError(t, err) {
+		body, _ := io.ReadAll(resp.Body)
+		assert.Equal(t, []byte{0x1f, 0x8b, 0xff}, body)
+	}
+}
@@ -2,7 +2,9 @@ package requestgen
 
 import (
 	"encoding/json"
+	"fmt"
 	"io"
+	"mime"
 	"net/http"
 	"strings"
 )
@@ -14,20 +16,77 @@ type Response struct {
 
 	// Body overrides the composited Body field.
 	Body []byte
+
+	// CompressedSize is the size of the gzip or deflate encoded body read from the wire.
+	// It's zero if the body is not encoded, or it's already decoded by http.Transport.
+	CompressedSize int64
+
+	// FromCache reports whether the response is answered by the ResponseCache of the API client,
+	// including the stale response revalidated by the API server with 304 Not Modified.
+	FromCache bool
 }
 
 // NewResponse is a wrapper of the http.Response instance, it reads the response body and close the file.
+// The gzip and deflate encoded body is decoded.
 func NewResponse(r *http.Response) (response *Response, err error) {
-	body, err := io.ReadAll(r.Body)
+	return NewResponseWithLimit(r, 0)
+}
+
+// NewResponseWithLimit is like NewResponse, but it stops reading the response body
+// and returns *ErrResponseTooLarge when the body exceeds the limit. Zero or negative limit means no limit.
+// The limit applies to the decoded body.
+func NewResponseWithLimit(r *http.Response, limit int64) (*Response, error) {
+	counter, err := decodeContentEncoding(r)
 	if err != nil {
+		_ = r.Body.Close()
 		return nil, err
 	}
 
+	if limit > 0 && counter == nil && r.ContentLength > limit {
+		_ = r.Body.Close()
+		return nil, &ErrResponseTooLarge{StatusCode: r.StatusCode, Header: r.Header, Limit: limit}
+	}
+
+	var reader io.Reader = r.Body
+	if limit > 0 {
+		reader = io.LimitReader(r.Body, limit+1)
+	}
+
+	body, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	if limit > 0 && int64(len(body)) > limit {
+		_ = r.Body.Close()
+		return nil, &ErrResponseTooLarge{StatusCode: r.StatusCode, Header: r.Header, Limit: limit, Body: body[:limit]}
+	}
+
 	err = r.Body.Close()
-	response = &Response{Response: r, Body: body}
+	response := &Response{Response: r, Body: body}
+	if counter != nil {
+		response.CompressedSize = counter.n
+	}
+
 	return response, err
 }
 
+// ErrResponseTooLarge is returned when the response body exceeds the size limit
+type ErrResponseTooLarge struct {
+	StatusCode int
+	Header     http.Header
+
+	// Limit is the size limit in bytes
+	Limit int64
+
+	// Body is the part of the body read before hitting the limit
+	Body []byte
+}
+
+func (e *ErrResponseTooLarge) Error() string {
+	return fmt.Sprintf("response body exceeds the size limit of %d bytes, status code: %d", e.Limit, e.StatusCode)
+}
+
 // String converts response body to string.
 // An empty string will be returned if error.
 func (r *Response) String() string {
@@ -43,17 +102,43 @@ func (r *Response) DecodeJSON(o interface{}) error {
 	return json.Unmarshal(r.Body, o)
 }
 
+// Decode decodes the response body with the decoder of the response content type.
+// JSON is used if the content type is missing or unknown, since some API servers do not send the correct content type.
+func (r *Response) Decode(o interface{}) error {
+	decoder, ok := LookupDecoder(r.Header.Get("content-type"))
+	if !ok {
+		return r.DecodeJSON(o)
+	}
+
+	return r.DecodeWith(decoder, o)
+}
+
+// DecodeWith decodes the response body with the given decoder regardless of the content type
+func (r *Response) DecodeWith(decoder Decoder, o interface{}) error {
+	// handle 204 - No Content
+	if r.StatusCode == 204 && len(r.Body) == 0 {
+		return nil
+	}
+
+	return decoder.Decode(r.Body, o)
+}
+
 func (r *Response) IsError() bool {
 	return r.StatusCode >= 400
 }
 
 func (r *Response) IsJSON() bool {
-	switch strings.ToLower(r.Header.Get("content-type")) {
-	case "text/json", "application/json", "application/json; charset=utf-8":
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("content-type"))
+	if err != nil {
+		return false
+	}
+
+	switch mediaType {
+	case "text/json", "application/json":
 		return true
 	}
 
-	return false
+	return strings.HasSuffix(mediaType, "+json")
 }
 
 func (r *Response) IsHTML() bool {
@@ -0,0 +1,67 @@
+package requestgen
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewResponseWithLimit(t *testing.T) {
+	newHttpResponse := func(body string, contentLength int64) *http.Response {
+		return &http.Response{
+			StatusCode:    http.StatusOK,
+			Header:        http.Header{"Content-Type": []string{"application/json"}},
+			Body:          io.NopCloser(bytes.NewBufferString(body)),
+			ContentLength: contentLength,
+		}
+	}
+
+	resp, err := NewResponseWithLimit(newHttpResponse(`{"ok":true}`, -1), 11)
+	if assert.NoError(t, err) {
+		assert.Equal(t, `{"ok":true}`, resp.String())
+	}
+
+	_, err = NewResponseWithLimit(newHttpResponse(`{"ok":true}`, -1), 10)
+	var tooLarge *ErrResponseTooLarge
+	if assert.True(t, errors.As(err, &tooLarge)) {
+		assert.Equal(t, http.StatusOK, tooLarge.StatusCode)
+		assert.Equal(t, "application/json", tooLarge.Header.Get("Content-Type"))
+		assert.Equal(t, `{"ok":true`, string(tooLarge.Body))
+	}
+
+	// rejected by the content length before reading the body
+	_, err = NewResponseWithLimit(newHttpResponse(`{"ok":true}`, 11), 10)
+	assert.True(t, errors.As(err, &tooLarge))
+}
+
+func TestBaseAPIClient_MaxResponseBytes(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(strings.Repeat("a", 100)))
+	})
+	client.MaxResponseBytes = 50
+
+	ctx := context.Background()
+	req, err := client.NewRequest(ctx, "GET", "/large", nil, nil)
+	assert.NoError(t, err)
+
+	_, err = client.SendRequest(req)
+	var tooLarge *ErrResponseTooLarge
+	assert.True(t, errors.As(err, &tooLarge))
+	assert.False(t, IsRetryable(err))
+
+	// the request meta overrides the limit of the client
+	ctx = WithRequestMeta(ctx, &RequestMeta{MaxResponseBytes: -1})
+	req, err = client.NewRequest(ctx, "GET", "/large", nil, nil)
+	assert.NoError(t, err)
+
+	resp, err := client.SendRequest(req)
+	if assert.NoError(t, err) {
+		assert.Len(t, resp.Body, 100)
+	}
+}
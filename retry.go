@@ -0,0 +1,221 @@
+package requestgen
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy decides whether a failed request should be sent again and how long to wait before the next attempt.
+type RetryPolicy interface {
+	// Backoff is called after each failed attempt with the attempt number (starting from 1),
+	// the response (nil if the request did not get a response) and the error of the attempt.
+	// It returns the delay before the next attempt, or false if the request should not be retried.
+	Backoff(attempt int, response *Response, err error) (time.Duration, bool)
+}
+
+// RequestRetryPolicy is a RetryPolicy that also looks at the failed request, e.g. its method and RequestMeta.
+// The API client calls BackoffRequest instead of Backoff if the RetryPolicy implements it.
+type RequestRetryPolicy interface {
+	RetryPolicy
+
+	BackoffRequest(req *http.Request, attempt int, response *Response, err error) (time.Duration, bool)
+}
+
+const (
+	defaultRetryMaxAttempts     = 3
+	defaultRetryInitialInterval = 200 * time.Millisecond
+	defaultRetryMaxInterval     = 10 * time.Second
+	defaultRetryMultiplier      = 2.0
+	defaultRetryJitter          = 0.5
+)
+
+// ExponentialBackoff is a RetryPolicy that retries with an exponentially growing delay.
+// The Retry-After header of the 429 and 503 responses is respected.
+// By default, the non-idempotent requests, e.g. POST, PUT and DELETE, are not retried after a network error
+// that might happen after the request was sent, unless the request has RequestMeta.IdempotencyKey,
+// since the server might have processed it, e.g. placed the order.
+// Zero fields fall back to the defaults, except Jitter, where zero means no jitter.
+type ExponentialBackoff struct {
+	// MaxAttempts is the maximum number of attempts, including the first one.
+	MaxAttempts int
+
+	// InitialInterval is the delay before the second attempt.
+	InitialInterval time.Duration
+
+	// MaxInterval caps the computed delay. It does not cap the delay given by the Retry-After header.
+	MaxInterval time.Duration
+
+	// Multiplier is the growth factor of the delay between attempts.
+	Multiplier float64
+
+	// Jitter randomizes the delay by the given factor, e.g. 0.5 gives a delay between 50% and 150% of the computed one.
+	Jitter float64
+
+	// ShouldRetry overrides the default retry decision (IsRetryable and the network error check of
+	// the non-idempotent requests) when it's set.
+	ShouldRetry func(response *Response, err error) bool
+}
+
+// NewExponentialBackoff creates an ExponentialBackoff with the default intervals and jitter
+func NewExponentialBackoff(maxAttempts int) *ExponentialBackoff {
+	return &ExponentialBackoff{
+		MaxAttempts:     maxAttempts,
+		InitialInterval: defaultRetryInitialInterval,
+		MaxInterval:     defaultRetryMaxInterval,
+		Multiplier:      defaultRetryMultiplier,
+		Jitter:          defaultRetryJitter,
+	}
+}
+
+func (b *ExponentialBackoff) Backoff(attempt int, response *Response, err error) (time.Duration, bool) {
+	return b.BackoffRequest(nil, attempt, response, err)
+}
+
+func (b *ExponentialBackoff) BackoffRequest(req *http.Request, attempt int, response *Response, err error) (time.Duration, bool) {
+	maxAttempts := b.MaxAttempts
+	if maxAttempts == 0 {
+		maxAttempts = defaultRetryMaxAttempts
+	}
+
+	if attempt >= maxAttempts {
+		return 0, false
+	}
+
+	if b.ShouldRetry != nil {
+		if !b.ShouldRetry(response, err) {
+			return 0, false
+		}
+	} else if !IsRetryable(err) || (req != nil && isAmbiguousFailure(req, err)) {
+		return 0, false
+	}
+
+	if response != nil {
+		switch response.StatusCode {
+		case http.StatusTooManyRequests, http.StatusServiceUnavailable:
+			if d, ok := parseRetryAfter(response.Header.Get("Retry-After")); ok {
+				return d, true
+			}
+		}
+	}
+
+	return b.interval(attempt), true
+}
+
+func (b *ExponentialBackoff) interval(attempt int) time.Duration {
+	initial, maxInterval, multiplier := b.InitialInterval, b.MaxInterval, b.Multiplier
+	if initial == 0 {
+		initial = defaultRetryInitialInterval
+	}
+	if maxInterval == 0 {
+		maxInterval = defaultRetryMaxInterval
+	}
+	if multiplier == 0 {
+		multiplier = defaultRetryMultiplier
+	}
+
+	d := float64(initial) * math.Pow(multiplier, float64(attempt-1))
+	if d > float64(maxInterval) {
+		d = float64(maxInterval)
+	}
+
+	if b.Jitter > 0 {
+		d += d * b.Jitter * (2*rand.Float64() - 1)
+	}
+
+	return time.Duration(d)
+}
+
+// isAmbiguousFailure reports whether the non-idempotent request failed with a network error
+// that might happen after the request was sent, e.g. a read timeout, so the server might have processed it.
+// The requests with an idempotency key are safe to send again.
+func isAmbiguousFailure(req *http.Request, err error) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace:
+		return false
+	}
+
+	if meta := RequestMetaFromContext(req.Context()); meta != nil && meta.IdempotencyKey != "" {
+		return false
+	}
+
+	if StatusCode(err) != 0 {
+		return false
+	}
+
+	// the request is not sent if the connection can not be made
+	var opErr *net.OpError
+	if errors.As(err, &opErr) && opErr.Op == "dial" {
+		return false
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
+
+// parseRetryAfter parses the Retry-After header value, which can be delay seconds or an HTTP date
+func parseRetryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if t, err := http.ParseTime(value); err == nil {
+		d := time.Until(t)
+		if d < 0 {
+			d = 0
+		}
+		return d, true
+	}
+
+	return 0, false
+}
+
+// sleepContext waits for the given duration, it returns the context error if the context is done before that.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// rewindRequest clones the request with a fresh body for the next attempt.
+// It returns false if the request body can not be rebuilt.
+func rewindRequest(req *http.Request) (*http.Request, bool) {
+	newReq := req.Clone(req.Context())
+	if req.Body == nil || req.Body == http.NoBody {
+		return newReq, true
+	}
+
+	if req.GetBody == nil {
+		return nil, false
+	}
+
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, false
+	}
+
+	newReq.Body = body
+	return newReq, true
+}
@@ -0,0 +1,163 @@
+package requestgen
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestClient(t *testing.T, handler http.HandlerFunc) *BaseAPIClient {
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	baseURL, err := url.Parse(server.URL)
+	assert.NoError(t, err)
+
+	return &BaseAPIClient{
+		BaseURL:    baseURL,
+		HttpClient: server.Client(),
+	}
+}
+
+func TestBaseAPIClient_SendRequest_Retry(t *testing.T) {
+	var attempts int32
+	var bodies []string
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+
+		if atomic.AddInt32(&attempts, 1) < 3 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		w.Write([]byte(`{"ok":true}`))
+	})
+	client.RetryPolicy = &ExponentialBackoff{MaxAttempts: 3, InitialInterval: time.Millisecond}
+
+	ctx := context.Background()
+	req, err := client.NewRequest(ctx, "POST", "/api/v1/orders", nil, map[string]interface{}{"symbol": "BTCUSDT"})
+	assert.NoError(t, err)
+
+	resp, err := client.SendRequest(req)
+	if assert.NoError(t, err) {
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+	}
+
+	assert.Equal(t, int32(3), atomic.LoadInt32(&attempts))
+	assert.Equal(t, []string{`{"symbol":"BTCUSDT"}`, `{"symbol":"BTCUSDT"}`, `{"symbol":"BTCUSDT"}`}, bodies)
+}
+
+func TestBaseAPIClient_SendRequest_RetryMaxAttempts(t *testing.T) {
+	var attempts int32
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&attempts, 1)
+		w.WriteHeader(http.StatusBadGateway)
+	})
+	client.RetryPolicy = &ExponentialBackoff{MaxAttempts: 2, InitialInterval: time.Millisecond}
+
+	req, err := client.NewRequest(context.Background(), "GET", "/api/v1/ping", nil, nil)
+	assert.NoError(t, err)
+
+	_, err = client.SendRequest(req)
+	if assert.Error(t, err) {
+		assert.IsType(t, &ErrResponse{}, err)
+	}
+	assert.Equal(t, int32(2), atomic.LoadInt32(&attempts))
+}
+
+func TestBaseAPIClient_SendRequest_NoRetryOnClientError(t *testing.T) {
+	var attempts int32
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&attempts, 1)
+		w.WriteHeader(http.StatusBadRequest)
+	})
+	client.RetryPolicy = NewExponentialBackoff(5)
+
+	req, err := client.NewRequest(context.Background(), "GET", "/api/v1/ping", nil, nil)
+	assert.NoError(t, err)
+
+	_, err = client.SendRequest(req)
+	assert.Error(t, err)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&attempts))
+}
+
+func TestBaseAPIClient_SendRequest_RetryStopsOnContextDone(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "60")
+		w.WriteHeader(http.StatusTooManyRequests)
+	})
+	client.RetryPolicy = NewExponentialBackoff(5)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	req, err := client.NewRequest(ctx, "GET", "/api/v1/ping", nil, nil)
+	assert.NoError(t, err)
+
+	start := time.Now()
+	_, err = client.SendRequest(req)
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+	assert.True(t, time.Since(start) < 5*time.Second)
+}
+
+func Test_parseRetryAfter(t *testing.T) {
+	d, ok := parseRetryAfter("3")
+	assert.True(t, ok)
+	assert.Equal(t, 3*time.Second, d)
+
+	d, ok = parseRetryAfter(time.Now().Add(time.Hour).UTC().Format(http.TimeFormat))
+	assert.True(t, ok)
+	assert.True(t, d > 59*time.Minute)
+
+	_, ok = parseRetryAfter("soon")
+	assert.False(t, ok)
+}
+
+func TestBaseAPIClient_SendRequest_NoRetryOnAmbiguousNetworkError(t *testing.T) {
+	var attempts int32
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&attempts, 1)
+
+		// drop the connection after the request is received
+		conn, _, err := w.(http.Hijacker).Hijack()
+		if assert.NoError(t, err) {
+			conn.Close()
+		}
+	})
+	client.RetryPolicy = &ExponentialBackoff{MaxAttempts: 2, InitialInterval: time.Millisecond}
+
+	send := func(ctx context.Context, method string) int32 {
+		atomic.StoreInt32(&attempts, 0)
+
+		req, err := client.NewRequest(ctx, method, "/api/v1/orders", nil, map[string]string{"side": "buy"})
+		assert.NoError(t, err)
+
+		_, err = client.SendRequest(req)
+		assert.Error(t, err)
+		return atomic.LoadInt32(&attempts)
+	}
+
+	ctx := context.Background()
+	assert.Equal(t, int32(1), send(ctx, "POST"), "the order might have been placed")
+	assert.Equal(t, int32(1), send(ctx, "DELETE"))
+	assert.Equal(t, int32(2), send(ctx, "GET"))
+
+	// the requests with an idempotency key are safe to send again
+	assert.Equal(t, int32(2), send(WithRequestMeta(ctx, &RequestMeta{IdempotencyKey: "order-1"}), "POST"))
+
+	// ShouldRetry opts in
+	client.RetryPolicy = &ExponentialBackoff{MaxAttempts: 2, InitialInterval: time.Millisecond, ShouldRetry: func(response *Response, err error) bool {
+		return IsRetryable(err)
+	}}
+	assert.Equal(t, int32(2), send(ctx, "POST"))
+}
@@ -0,0 +1,272 @@
+package requestgen
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"hash"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Signer signs the authenticated requests, usually by setting the key and the signature headers.
+type Signer interface {
+	// Sign signs the request, body is the content of the request body, it's nil if the body is streamed.
+	Sign(req *http.Request, body []byte) error
+}
+
+// SignerFunc is an adapter to allow the use of ordinary functions as Signer
+type SignerFunc func(req *http.Request, body []byte) error
+
+func (f SignerFunc) Sign(req *http.Request, body []byte) error {
+	return f(req, body)
+}
+
+// SignatureEncoding is the output encoding of the signature
+type SignatureEncoding int
+
+const (
+	SignatureEncodingHex SignatureEncoding = iota
+	SignatureEncodingBase64
+)
+
+// DefaultPrehash is the default prehash layout of HMACSigner
+const DefaultPrehash = "{timestamp}{method}{path}{body}"
+
+// HMACSigner signs the requests with the HMAC of the prehash string.
+//
+// The prehash string is built from the Prehash layout by replacing the placeholders:
+//
+//	{timestamp}  the timestamp, which is also sent in TimestampHeader
+//	{method}     the upper-case HTTP method
+//	{path}       the request path with the query string as sent, e.g. /api/v1/orders?symbol=BTC-USDT
+//	{query}      the canonical query string, see CanonicalQueryString
+//	{body}       the request body
+//	{bodyDigest} the hex SHA256 digest of the request body, see BodyDigest
+//	{key}        the API key
+//
+// For example, the KuCoin signature is:
+//
+//	signer := &requestgen.HMACSigner{
+//		Key:             key,
+//		Secret:          secret,
+//		Encoding:        requestgen.SignatureEncodingBase64,
+//		KeyHeader:       "KC-API-KEY",
+//		SignatureHeader: "KC-API-SIGN",
+//		TimestampHeader: "KC-API-TIMESTAMP",
+//	}
+type HMACSigner struct {
+	Key, Secret string
+
+	// Prehash is the layout of the string to be signed, DefaultPrehash is used if it's empty.
+	Prehash string
+
+	// Hash is the hash function of HMAC, e.g. sha512.New, sha256.New is used if it's nil.
+	Hash func() hash.Hash
+
+	// Encoding is the output encoding of the signature, hex by default.
+	Encoding SignatureEncoding
+
+	// KeyHeader, SignatureHeader and TimestampHeader are the header names of the API key, the signature and the timestamp.
+	// The header is not set if its name is empty, except SignatureHeader, which is required.
+	KeyHeader, SignatureHeader, TimestampHeader string
+
+	// Headers are the additional headers set on the signed requests, e.g. the passphrase header.
+	Headers map[string]string
+
+	// Timestamp formats the signing time, the unix milliseconds are used if it's nil.
+	Timestamp func(t time.Time) string
+
+	// Now returns the signing time, time.Now is used if it's nil.
+	Now func() time.Time
+}
+
+func (s *HMACSigner) Sign(req *http.Request, body []byte) error {
+	if s.SignatureHeader == "" {
+		return errors.New("requestgen: HMACSigner.SignatureHeader is empty")
+	}
+
+	if s.Secret == "" {
+		return errors.New("requestgen: HMACSigner.Secret is empty")
+	}
+
+	now := time.Now
+	if s.Now != nil {
+		now = s.Now
+	}
+
+	var timestamp string
+	if s.Timestamp != nil {
+		timestamp = s.Timestamp(now())
+	} else {
+		timestamp = strconv.FormatInt(now().UnixMilli(), 10)
+	}
+
+	layout := s.Prehash
+	if layout == "" {
+		layout = DefaultPrehash
+	}
+
+	replacer := strings.NewReplacer(
+		"{timestamp}", timestamp,
+		"{method}", strings.ToUpper(req.Method),
+		"{path}", req.URL.RequestURI(),
+		"{query}", CanonicalQueryString(req.URL.Query()),
+		"{body}", string(body),
+		"{bodyDigest}", BodyDigest(body, nil),
+		"{key}", s.Key,
+	)
+
+	if s.KeyHeader != "" {
+		req.Header.Set(s.KeyHeader, s.Key)
+	}
+
+	if s.TimestampHeader != "" {
+		req.Header.Set(s.TimestampHeader, timestamp)
+	}
+
+	for key, value := range s.Headers {
+		req.Header.Set(key, value)
+	}
+
+	req.Header.Set(s.SignatureHeader, s.Sum(replacer.Replace(layout)))
+	return nil
+}
+
+// Sum returns the encoded HMAC of the payload, it can be used for signing other values, e.g. the passphrase.
+func (s *HMACSigner) Sum(payload string) string {
+	h := s.Hash
+	if h == nil {
+		h = sha256.New
+	}
+
+	mac := hmac.New(h, []byte(s.Secret))
+	mac.Write([]byte(payload))
+	sum := mac.Sum(nil)
+
+	if s.Encoding == SignatureEncodingBase64 {
+		return base64.StdEncoding.EncodeToString(sum)
+	}
+
+	return hex.EncodeToString(sum)
+}
+
+// CanonicalQueryString encodes the query parameters sorted by the key and then the value,
+// the spaces are encoded as %20 instead of +.
+func CanonicalQueryString(values url.Values) string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var sb strings.Builder
+	for _, key := range keys {
+		vs := append([]string(nil), values[key]...)
+		sort.Strings(vs)
+
+		for _, v := range vs {
+			if sb.Len() > 0 {
+				sb.WriteByte('&')
+			}
+
+			sb.WriteString(queryEscape(key))
+			sb.WriteByte('=')
+			sb.WriteString(queryEscape(v))
+		}
+	}
+
+	return sb.String()
+}
+
+func queryEscape(s string) string {
+	return strings.ReplaceAll(url.QueryEscape(s), "+", "%20")
+}
+
+// BodyDigest returns the hex digest of the body, sha256.New is used if h is nil.
+func BodyDigest(body []byte, h func() hash.Hash) string {
+	if h == nil {
+		h = sha256.New
+	}
+
+	digest := h()
+	digest.Write(body)
+	return hex.EncodeToString(digest.Sum(nil))
+}
+
+// NewAuthenticatedRequest builds the request like NewRequest, sets the Authorization header with the token of
+// the TokenSource, and signs it with the Signer. At least one of TokenSource and Signer must be set.
+func (c *BaseAPIClient) NewAuthenticatedRequest(
+	ctx context.Context, method, refURL string, params url.Values, payload interface{},
+) (*http.Request, error) {
+	if c.Signer == nil && c.TokenSource == nil {
+		return nil, errors.New("requestgen: neither BaseAPIClient.Signer nor BaseAPIClient.TokenSource is set for the authenticated request")
+	}
+
+	req, err := c.NewRequest(ctx, method, refURL, params, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.TokenSource != nil {
+		token, err := c.TokenSource.Token(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		req = withRequestToken(req, token)
+	}
+
+	if c.Signer == nil {
+		return req, nil
+	}
+
+	req = req.WithContext(context.WithValue(req.Context(), signedRequestKey{}, true))
+	if err := c.sign(req); err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+type signedRequestKey struct{}
+
+// isSignedRequest reports whether the request is signed by NewAuthenticatedRequest,
+// so that the client can sign it again with a fresh timestamp before sending it again
+func isSignedRequest(req *http.Request) bool {
+	signed, _ := req.Context().Value(signedRequestKey{}).(bool)
+	return signed
+}
+
+// sign signs the request with the Signer and a copy of the request body
+func (c *BaseAPIClient) sign(req *http.Request) error {
+	body, err := requestBody(req)
+	if err != nil {
+		return err
+	}
+
+	return c.Signer.Sign(req, body)
+}
+
+// requestBody reads a copy of the request body, nil is returned if the body can not be rebuilt, e.g. a streamed body.
+func requestBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody || req.GetBody == nil {
+		return nil, nil
+	}
+
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	return io.ReadAll(body)
+}
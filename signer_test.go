@@ -0,0 +1,139 @@
+package requestgen
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/hex"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func hmacHex(secret, payload string) string {
+	mac := hmac.New(sha512.New, []byte(secret))
+	mac.Write([]byte(payload))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestHMACSigner_Sign(t *testing.T) {
+	now := time.UnixMilli(1700000000123)
+	signer := &HMACSigner{
+		Key:             "key",
+		Secret:          "secret",
+		Prehash:         "{timestamp}\n{method}\n{path}\n{query}\n{bodyDigest}",
+		Hash:            sha512.New,
+		KeyHeader:       "X-API-KEY",
+		SignatureHeader: "X-API-SIGN",
+		TimestampHeader: "X-API-TIMESTAMP",
+		Headers:         map[string]string{"X-API-VERSION": "2"},
+		Now:             func() time.Time { return now },
+	}
+
+	req, err := http.NewRequest("post", "https://api.example.com/api/v1/orders?symbol=BTC-USDT&side=buy", nil)
+	assert.NoError(t, err)
+
+	body := []byte(`{"size":"1"}`)
+	assert.NoError(t, signer.Sign(req, body))
+
+	prehash := "1700000000123\nPOST\n/api/v1/orders?symbol=BTC-USDT&side=buy\nside=buy&symbol=BTC-USDT\n" + BodyDigest(body, nil)
+	assert.Equal(t, hmacHex("secret", prehash), req.Header.Get("X-API-SIGN"))
+	assert.Equal(t, "key", req.Header.Get("X-API-KEY"))
+	assert.Equal(t, "1700000000123", req.Header.Get("X-API-TIMESTAMP"))
+	assert.Equal(t, "2", req.Header.Get("X-API-VERSION"))
+
+	assert.Error(t, (&HMACSigner{Secret: "secret"}).Sign(req, nil))
+}
+
+func TestHMACSigner_Sum(t *testing.T) {
+	signer := &HMACSigner{Secret: "secret", Encoding: SignatureEncodingBase64}
+
+	mac := hmac.New(sha256.New, []byte("secret"))
+	mac.Write([]byte("passphrase"))
+	assert.Equal(t, base64.StdEncoding.EncodeToString(mac.Sum(nil)), signer.Sum("passphrase"))
+}
+
+func TestCanonicalQueryString(t *testing.T) {
+	values := url.Values{
+		"symbol": {"BTC-USDT"},
+		"b":      {"2", "1"},
+		"a":      {"hello world"},
+	}
+	assert.Equal(t, "a=hello%20world&b=1&b=2&symbol=BTC-USDT", CanonicalQueryString(values))
+	assert.Equal(t, "", CanonicalQueryString(nil))
+}
+
+func TestBodyDigest(t *testing.T) {
+	assert.Equal(t, "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855", BodyDigest(nil, nil))
+}
+
+func TestBaseAPIClient_NewAuthenticatedRequest(t *testing.T) {
+	baseURL, _ := url.Parse("https://api.example.com/api/")
+	client := &BaseAPIClient{BaseURL: baseURL}
+
+	_, err := client.NewAuthenticatedRequest(context.Background(), "GET", "v1/orders", nil, nil)
+	assert.Error(t, err)
+
+	var signedPath string
+	var signedBody []byte
+	client.Signer = SignerFunc(func(req *http.Request, body []byte) error {
+		signedPath, signedBody = req.URL.RequestURI(), body
+		req.Header.Set("X-SIGNED", "1")
+		return nil
+	})
+
+	req, err := client.NewAuthenticatedRequest(context.Background(), "POST", "v1/orders", url.Values{"symbol": {"BTC-USDT"}}, map[string]string{"side": "buy"})
+	if assert.NoError(t, err) {
+		assert.Equal(t, "1", req.Header.Get("X-SIGNED"))
+		assert.Equal(t, "/api/v1/orders?symbol=BTC-USDT", signedPath)
+		assert.Equal(t, `{"side":"buy"}`, string(signedBody))
+
+		// the body is still readable after signing
+		body, _ := requestBody(req)
+		assert.Equal(t, signedBody, body)
+	}
+}
+
+func TestBaseAPIClient_SendRequest_RetrySignsAgain(t *testing.T) {
+	var timestamps, signatures []string
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		timestamps = append(timestamps, r.Header.Get("X-API-TIMESTAMP"))
+		signatures = append(signatures, r.Header.Get("X-API-SIGN"))
+
+		if len(timestamps) < 2 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		w.Write([]byte(`{}`))
+	})
+	client.RetryPolicy = &ExponentialBackoff{MaxAttempts: 2, InitialInterval: time.Millisecond}
+
+	now := time.UnixMilli(1700000000000)
+	client.Signer = &HMACSigner{
+		Key:             "key",
+		Secret:          "secret",
+		SignatureHeader: "X-API-SIGN",
+		TimestampHeader: "X-API-TIMESTAMP",
+		Now: func() time.Time {
+			now = now.Add(time.Second)
+			return now
+		},
+	}
+
+	req, err := client.NewAuthenticatedRequest(context.Background(), "POST", "/api/v1/orders", nil, map[string]string{"side": "buy"})
+	assert.NoError(t, err)
+
+	_, err = client.SendRequest(req)
+	assert.NoError(t, err)
+
+	if assert.Len(t, timestamps, 2) {
+		assert.Equal(t, []string{"1700000001000", "1700000002000"}, timestamps)
+		assert.NotEqual(t, signatures[0], signatures[1])
+	}
+}
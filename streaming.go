@@ -0,0 +1,191 @@
+package requestgen
+
+import (
+	"encoding/json"
+	"fmt"
+	"iter"
+	"net/http"
+	"time"
+)
+
+// StreamingAPIClient sends the request without buffering the response body
+type StreamingAPIClient interface {
+	SendStreamRequest(req *http.Request) (*StreamingResponse, error)
+}
+
+// StreamingResponse is the response of which the body is read incrementally.
+// It implements io.ReadCloser, the caller must close it after reading the body.
+type StreamingResponse struct {
+	*http.Response
+}
+
+func (r *StreamingResponse) Read(p []byte) (int, error) {
+	return r.Response.Body.Read(p)
+}
+
+func (r *StreamingResponse) Close() error {
+	return r.Response.Body.Close()
+}
+
+// Decoder returns a json.Decoder that reads from the response body
+func (r *StreamingResponse) Decoder() *json.Decoder {
+	return json.NewDecoder(r.Response.Body)
+}
+
+// SendStreamRequest sends the request to the API server and returns the response without reading the body.
+// Error responses are buffered and returned as *ErrResponse, just like SendRequest,
+// MaxResponseBytes only applies to these error responses.
+// Streaming requests are sent once, the middlewares and the RetryPolicy are not applied,
+// but the CircuitBreaker is, with the result decided by the response headers.
+// Note that the timeout of HttpClient also covers reading the response body.
+func (c *BaseAPIClient) SendStreamRequest(req *http.Request) (_ *StreamingResponse, err error) {
+	if c.HttpClient == nil {
+		c.HttpClient = defaultHttpClient
+	}
+
+	// the result of the streaming request is recorded once the response headers are received,
+	// the errors of reading the body afterwards are not counted
+	var recorded *Response
+	if c.CircuitBreaker != nil {
+		key := circuitKey(req)
+		if err := c.CircuitBreaker.Allow(key); err != nil {
+			return nil, err
+		}
+
+		defer func() {
+			c.CircuitBreaker.Record(req.Context(), key, recorded, err)
+		}()
+	}
+
+	c.negotiateCompression(req)
+
+	// the observed duration of the streaming response is the time to the response headers,
+	// and the size of the response body is the content length
+	var statusCode int
+	var responseBytes int64 = -1
+	if c.Observer != nil {
+		start := time.Now()
+		defer func() {
+			c.observe(req, statusCode, responseBytes, start, err)
+		}()
+	}
+
+	resp, err := c.HttpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.AdaptiveRateLimiter != nil {
+		c.AdaptiveRateLimiter.Update(resp.Header)
+	}
+
+	statusCode = resp.StatusCode
+	if resp.StatusCode < 400 {
+		if _, err := decodeContentEncoding(resp); err != nil {
+			_ = resp.Body.Close()
+			return nil, err
+		}
+
+		responseBytes = resp.ContentLength
+		recorded = &Response{Response: resp}
+		return &StreamingResponse{Response: resp}, nil
+	}
+
+	response, err := NewResponseWithLimit(resp, c.maxResponseBytes(req))
+	if err != nil {
+		return nil, err
+	}
+
+	responseBytes = int64(len(response.Body))
+	recorded = response
+
+	if c.ErrorType != nil {
+		if apiErr, ok := newAPIError(c.ErrorType); ok {
+			return nil, DecodeAPIError(req, response, apiErr)
+		}
+	}
+
+	return nil, &ErrResponse{Response: response, Body: response.Body, Request: req}
+}
+
+// DecodeJSONArray returns an iterator that decodes the elements of the JSON array in the response body one by one.
+// The path is the object keys leading to the array, e.g. "data" for {"code":"200000","data":[...]},
+// the array is expected at the top level if the path is empty.
+// The iteration stops at the first error.
+func DecodeJSONArray[T any](r *StreamingResponse, path ...string) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		var zero T
+
+		decoder := r.Decoder()
+		if err := seekJSONPath(decoder, path); err != nil {
+			yield(zero, err)
+			return
+		}
+
+		if err := expectJSONDelim(decoder, '['); err != nil {
+			yield(zero, err)
+			return
+		}
+
+		for decoder.More() {
+			var item T
+			if err := decoder.Decode(&item); err != nil {
+				yield(zero, err)
+				return
+			}
+
+			if !yield(item, nil) {
+				return
+			}
+		}
+
+		if err := expectJSONDelim(decoder, ']'); err != nil {
+			yield(zero, err)
+		}
+	}
+}
+
+// seekJSONPath moves the decoder to the value of the given object key path
+func seekJSONPath(decoder *json.Decoder, path []string) error {
+	for _, key := range path {
+		if err := expectJSONDelim(decoder, '{'); err != nil {
+			return err
+		}
+
+		for {
+			if !decoder.More() {
+				return fmt.Errorf("json key %q not found", key)
+			}
+
+			token, err := decoder.Token()
+			if err != nil {
+				return err
+			}
+
+			if token == key {
+				break
+			}
+
+			// skip the value of the other keys
+			var skipped json.RawMessage
+			if err := decoder.Decode(&skipped); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func expectJSONDelim(decoder *json.Decoder, delim json.Delim) error {
+	token, err := decoder.Token()
+	if err != nil {
+		return err
+	}
+
+	if token != delim {
+		return fmt.Errorf("unexpected json token %v, expecting %v", token, delim)
+	}
+
+	return nil
+}
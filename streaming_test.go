@@ -0,0 +1,78 @@
+package requestgen
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBaseAPIClient_SendStreamRequest(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/error" {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"message":"bad request"}`))
+			return
+		}
+
+		fmt.Fprint(w, `{"total":3,"items":[`)
+		for i := 1; i <= 3; i++ {
+			if i > 1 {
+				fmt.Fprint(w, ",")
+			}
+			fmt.Fprintf(w, `{"id":%d}`, i)
+			w.(http.Flusher).Flush()
+		}
+		fmt.Fprint(w, `]}`)
+	})
+
+	ctx := context.Background()
+	req, err := client.NewRequest(ctx, "GET", "/items", nil, nil)
+	assert.NoError(t, err)
+
+	resp, err := client.SendStreamRequest(req)
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer resp.Close()
+
+	type item struct {
+		ID int `json:"id"`
+	}
+
+	var ids []int
+	for it, err := range DecodeJSONArray[item](resp, "items") {
+		assert.NoError(t, err)
+		ids = append(ids, it.ID)
+	}
+	assert.Equal(t, []int{1, 2, 3}, ids)
+
+	req, err = client.NewRequest(ctx, "GET", "/error", nil, nil)
+	assert.NoError(t, err)
+
+	_, err = client.SendStreamRequest(req)
+	if assert.Error(t, err) {
+		assert.Equal(t, http.StatusBadRequest, StatusCode(err))
+	}
+}
+
+func TestDecodeJSONArray_KeyNotFound(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"total":0}`)
+	})
+
+	req, err := client.NewRequest(context.Background(), "GET", "/items", nil, nil)
+	assert.NoError(t, err)
+
+	resp, err := client.SendStreamRequest(req)
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer resp.Close()
+
+	for _, err := range DecodeJSONArray[int](resp, "items") {
+		assert.EqualError(t, err, `json key "items" not found`)
+	}
+}
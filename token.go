@@ -0,0 +1,252 @@
+package requestgen
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+const defaultTokenRefreshBefore = time.Minute
+
+// Token is the access token of the authenticated requests
+type Token struct {
+	AccessToken string
+
+	// TokenType is the authorization scheme, Bearer is used if it's empty.
+	TokenType string
+
+	RefreshToken string
+
+	// Expiry is the expiration time of the access token, zero means the token never expires.
+	Expiry time.Time
+}
+
+// Type returns the authorization scheme of the token
+func (t *Token) Type() string {
+	if t.TokenType == "" || strings.EqualFold(t.TokenType, "bearer") {
+		return "Bearer"
+	}
+
+	return t.TokenType
+}
+
+// SetAuthHeader sets the Authorization header of the request
+func (t *Token) SetAuthHeader(req *http.Request) {
+	req.Header.Set("Authorization", t.Type()+" "+t.AccessToken)
+}
+
+// TokenSource provides the access tokens for the authenticated requests
+type TokenSource interface {
+	Token(ctx context.Context) (*Token, error)
+}
+
+// TokenSourceFunc is an adapter to allow the use of ordinary functions as TokenSource
+type TokenSourceFunc func(ctx context.Context) (*Token, error)
+
+func (f TokenSourceFunc) Token(ctx context.Context) (*Token, error) {
+	return f(ctx)
+}
+
+// CachedTokenSource caches the token of the underlying source, and refreshes it before it expires.
+// The concurrent refreshes are serialized, so only one request is sent to the token endpoint at a time.
+type CachedTokenSource struct {
+	Source TokenSource
+
+	// RefreshBefore is how long before the expiry the token is refreshed, one minute by default.
+	// It's capped by half of the token lifetime, so that the short-lived tokens are not refreshed on every request.
+	RefreshBefore time.Duration
+
+	mu        sync.Mutex
+	token     *Token
+	refreshAt time.Time
+}
+
+// NewCachedTokenSource creates a CachedTokenSource of the given source
+func NewCachedTokenSource(source TokenSource) *CachedTokenSource {
+	return &CachedTokenSource{Source: source, RefreshBefore: defaultTokenRefreshBefore}
+}
+
+func (s *CachedTokenSource) Token(ctx context.Context) (*Token, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.token != nil && (s.refreshAt.IsZero() || time.Now().Before(s.refreshAt)) {
+		return s.token, nil
+	}
+
+	token, err := s.Source.Token(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	s.token = token
+	s.refreshAt = time.Time{}
+	if !token.Expiry.IsZero() {
+		refreshBefore := s.RefreshBefore
+		if lifetime := time.Until(token.Expiry); refreshBefore > lifetime/2 {
+			refreshBefore = lifetime / 2
+		}
+
+		s.refreshAt = token.Expiry.Add(-refreshBefore)
+	}
+
+	return token, nil
+}
+
+// Invalidate drops the cached token if it's the given token, e.g. when the token is rejected by the API server.
+func (s *CachedTokenSource) Invalidate(token *Token) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.token == token {
+		s.token = nil
+	}
+}
+
+// OAuth2TokenSource requests the tokens from the OAuth2 token endpoint with the client credentials grant,
+// or the refresh token grant if RefreshToken is set. The rotated refresh token is kept for the next request.
+// It's usually wrapped by CachedTokenSource.
+type OAuth2TokenSource struct {
+	TokenURL     string
+	ClientID     string
+	ClientSecret string
+	Scopes       []string
+
+	// RefreshToken is used for the refresh token grant
+	RefreshToken string
+
+	// BasicAuth sends the client credentials in the Authorization header instead of the form body
+	BasicAuth bool
+
+	// HttpClient sends the token requests, the default HTTP client is used if it's nil.
+	HttpClient *http.Client
+
+	mu sync.Mutex
+}
+
+type oauth2TokenResponse struct {
+	AccessToken  string `json:"access_token"`
+	TokenType    string `json:"token_type"`
+	RefreshToken string `json:"refresh_token"`
+	ExpiresIn    int64  `json:"expires_in"`
+}
+
+func (s *OAuth2TokenSource) Token(ctx context.Context) (*Token, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	form := url.Values{}
+	if s.RefreshToken != "" {
+		form.Set("grant_type", "refresh_token")
+		form.Set("refresh_token", s.RefreshToken)
+	} else {
+		form.Set("grant_type", "client_credentials")
+	}
+
+	if len(s.Scopes) > 0 {
+		form.Set("scope", strings.Join(s.Scopes, " "))
+	}
+
+	if !s.BasicAuth {
+		form.Set("client_id", s.ClientID)
+		form.Set("client_secret", s.ClientSecret)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", s.TokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", FormContentType)
+	req.Header.Set("Accept", "application/json")
+	if s.BasicAuth {
+		req.SetBasicAuth(url.QueryEscape(s.ClientID), url.QueryEscape(s.ClientSecret))
+	}
+
+	httpClient := s.HttpClient
+	if httpClient == nil {
+		httpClient = defaultHttpClient
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := NewResponse(resp)
+	if err != nil {
+		return nil, err
+	}
+
+	if response.IsError() {
+		return nil, &ErrResponse{Response: response, Body: response.Body, Request: req}
+	}
+
+	var tokenResponse oauth2TokenResponse
+	if err := response.DecodeJSON(&tokenResponse); err != nil {
+		return nil, err
+	}
+
+	if tokenResponse.AccessToken == "" {
+		return nil, errors.New("requestgen: the token response has no access_token")
+	}
+
+	token := &Token{
+		AccessToken:  tokenResponse.AccessToken,
+		TokenType:    tokenResponse.TokenType,
+		RefreshToken: tokenResponse.RefreshToken,
+	}
+
+	if tokenResponse.ExpiresIn > 0 {
+		token.Expiry = time.Now().Add(time.Duration(tokenResponse.ExpiresIn) * time.Second)
+	}
+
+	if tokenResponse.RefreshToken != "" {
+		s.RefreshToken = tokenResponse.RefreshToken
+	}
+
+	return token, nil
+}
+
+type requestTokenKey struct{}
+
+// withRequestToken attaches the token to the request, so that the client can re-authenticate the request
+func withRequestToken(req *http.Request, token *Token) *http.Request {
+	token.SetAuthHeader(req)
+	return req.WithContext(context.WithValue(req.Context(), requestTokenKey{}, token))
+}
+
+func requestToken(req *http.Request) *Token {
+	token, _ := req.Context().Value(requestTokenKey{}).(*Token)
+	return token
+}
+
+// reauthenticate drops the rejected token, and rebuilds the request with a new token from the TokenSource
+func (c *BaseAPIClient) reauthenticate(req *http.Request, rejected *Token) (*http.Request, bool) {
+	if invalidator, ok := c.TokenSource.(interface{ Invalidate(token *Token) }); ok {
+		invalidator.Invalidate(rejected)
+	}
+
+	token, err := c.TokenSource.Token(req.Context())
+	if err != nil {
+		return nil, false
+	}
+
+	nextReq, ok := rewindRequest(req)
+	if !ok {
+		return nil, false
+	}
+
+	nextReq = withRequestToken(nextReq, token)
+	if c.Signer != nil {
+		if err := c.sign(nextReq); err != nil {
+			return nil, false
+		}
+	}
+
+	return nextReq, true
+}
@@ -0,0 +1,166 @@
+package requestgen
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOAuth2TokenSource(t *testing.T) {
+	var grants []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.NoError(t, r.ParseForm())
+		grants = append(grants, r.PostForm.Get("grant_type"))
+
+		switch r.PostForm.Get("grant_type") {
+		case "client_credentials":
+			assert.Equal(t, "client", r.PostForm.Get("client_id"))
+			assert.Equal(t, "secret", r.PostForm.Get("client_secret"))
+			assert.Equal(t, "read trade", r.PostForm.Get("scope"))
+
+		case "refresh_token":
+			assert.Equal(t, "refresh-1", r.PostForm.Get("refresh_token"))
+		}
+
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"access_token":  fmt.Sprintf("token-%d", len(grants)),
+			"token_type":    "bearer",
+			"refresh_token": fmt.Sprintf("refresh-%d", len(grants)),
+			"expires_in":    3600,
+		})
+	}))
+	defer server.Close()
+
+	source := &OAuth2TokenSource{
+		TokenURL:     server.URL,
+		ClientID:     "client",
+		ClientSecret: "secret",
+		Scopes:       []string{"read", "trade"},
+		HttpClient:   server.Client(),
+	}
+
+	token, err := source.Token(context.Background())
+	if assert.NoError(t, err) {
+		assert.Equal(t, "token-1", token.AccessToken)
+		assert.Equal(t, "Bearer", token.Type())
+		assert.True(t, time.Until(token.Expiry) > 59*time.Minute)
+	}
+
+	// the refresh token grant is used with the rotated refresh token
+	token, err = source.Token(context.Background())
+	if assert.NoError(t, err) {
+		assert.Equal(t, "token-2", token.AccessToken)
+		assert.Equal(t, "refresh-2", source.RefreshToken)
+	}
+
+	assert.Equal(t, []string{"client_credentials", "refresh_token"}, grants)
+}
+
+func TestOAuth2TokenSource_Error(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"error":"invalid_client"}`))
+	}))
+	defer server.Close()
+
+	source := &OAuth2TokenSource{TokenURL: server.URL, HttpClient: server.Client()}
+	_, err := source.Token(context.Background())
+	assert.Equal(t, http.StatusBadRequest, StatusCode(err))
+}
+
+func TestCachedTokenSource(t *testing.T) {
+	var calls int32
+	source := NewCachedTokenSource(TokenSourceFunc(func(ctx context.Context) (*Token, error) {
+		n := atomic.AddInt32(&calls, 1)
+		time.Sleep(10 * time.Millisecond)
+		return &Token{AccessToken: fmt.Sprintf("token-%d", n), Expiry: time.Now().Add(200 * time.Millisecond)}, nil
+	}))
+
+	// the concurrent requests share one refresh
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			token, err := source.Token(context.Background())
+			assert.NoError(t, err)
+			assert.Equal(t, "token-1", token.AccessToken)
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+
+	// the token is refreshed at the half of its lifetime, before it expires
+	time.Sleep(120 * time.Millisecond)
+	token, err := source.Token(context.Background())
+	if assert.NoError(t, err) {
+		assert.Equal(t, "token-2", token.AccessToken)
+	}
+
+	source.Invalidate(&Token{})
+	token, _ = source.Token(context.Background())
+	assert.Equal(t, "token-2", token.AccessToken)
+
+	source.Invalidate(token)
+	token, _ = source.Token(context.Background())
+	assert.Equal(t, "token-3", token.AccessToken)
+}
+
+func TestBaseAPIClient_TokenSource_Reauthenticate(t *testing.T) {
+	var authorizations []string
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		authorizations = append(authorizations, r.Header.Get("Authorization"))
+		if r.Header.Get("Authorization") != "Bearer token-2" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		w.Write([]byte(`{"ok":true}`))
+	})
+
+	var calls int32
+	client.TokenSource = NewCachedTokenSource(TokenSourceFunc(func(ctx context.Context) (*Token, error) {
+		return &Token{AccessToken: fmt.Sprintf("token-%d", atomic.AddInt32(&calls, 1))}, nil
+	}))
+
+	req, err := client.NewAuthenticatedRequest(context.Background(), "POST", "/api/v1/orders", nil, map[string]string{"side": "buy"})
+	assert.NoError(t, err)
+
+	resp, err := client.SendRequest(req)
+	if assert.NoError(t, err) {
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+	}
+	assert.Equal(t, []string{"Bearer token-1", "Bearer token-2"}, authorizations)
+
+	// the new token is cached
+	req, err = client.NewAuthenticatedRequest(context.Background(), "GET", "/api/v1/orders", nil, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "Bearer token-2", req.Header.Get("Authorization"))
+}
+
+func TestBaseAPIClient_TokenSource_ReauthenticateOnce(t *testing.T) {
+	var attempts int32
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&attempts, 1)
+		w.WriteHeader(http.StatusUnauthorized)
+	})
+	client.TokenSource = TokenSourceFunc(func(ctx context.Context) (*Token, error) {
+		return &Token{AccessToken: "token"}, nil
+	})
+
+	req, err := client.NewAuthenticatedRequest(context.Background(), "GET", "/api/v1/orders", nil, nil)
+	assert.NoError(t, err)
+
+	_, err = client.SendRequest(req)
+	assert.IsType(t, &ErrResponse{}, err)
+	assert.True(t, IsAuthError(err))
+	assert.Equal(t, int32(2), atomic.LoadInt32(&attempts))
+}
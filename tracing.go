@@ -0,0 +1,113 @@
+package requestgen
+
+import (
+	"context"
+	"sync"
+)
+
+// Attribute is a key-value pair attached to a span or a span event
+type Attribute struct {
+	Key   string
+	Value interface{}
+}
+
+// Attr creates an Attribute
+func Attr(key string, value interface{}) Attribute {
+	return Attribute{Key: key, Value: value}
+}
+
+// Span is a traced operation, it can be bridged to the span of any tracing SDK.
+type Span interface {
+	// SetAttributes sets the attributes of the span
+	SetAttributes(attributes ...Attribute)
+
+	// AddEvent adds a timestamped event to the span
+	AddEvent(name string, attributes ...Attribute)
+
+	// RecordError records the error of the operation
+	RecordError(err error)
+
+	// End ends the span
+	End()
+}
+
+// Tracer starts the spans, the returned context carries the new span, so that the spans of the HTTP client
+// (e.g. from an instrumented http.RoundTripper) become its children.
+type Tracer interface {
+	StartSpan(ctx context.Context, name string) (context.Context, Span)
+}
+
+// TracerFunc is an adapter to allow the use of ordinary functions as Tracer
+type TracerFunc func(ctx context.Context, name string) (context.Context, Span)
+
+func (f TracerFunc) StartSpan(ctx context.Context, name string) (context.Context, Span) {
+	return f(ctx, name)
+}
+
+// The span attribute keys set by the generated requests
+const (
+	AttrRequestType   = "requestgen.request_type"
+	AttrHTTPMethod    = "http.method"
+	AttrHTTPRoute     = "http.route"
+	AttrStatusCode    = "http.status_code"
+	AttrRateLimitWait = "requestgen.rate_limit_wait"
+)
+
+var (
+	tracerMu sync.RWMutex
+	tracer   Tracer
+)
+
+// SetTracer registers the global tracer used by the generated requests, nil disables the tracing.
+func SetTracer(t Tracer) {
+	tracerMu.Lock()
+	tracer = t
+	tracerMu.Unlock()
+}
+
+// StartSpan starts a span with the registered tracer, a no-op span is returned if no tracer is registered.
+func StartSpan(ctx context.Context, name string) (context.Context, Span) {
+	tracerMu.RLock()
+	t := tracer
+	tracerMu.RUnlock()
+
+	if t == nil {
+		return ctx, noopSpan{}
+	}
+
+	return t.StartSpan(ctx, name)
+}
+
+// StartRequestSpan starts the span of the generated request with the request type name, the method and the path template
+func StartRequestSpan(ctx context.Context, meta *RequestMeta) (context.Context, Span) {
+	ctx, span := StartSpan(ctx, meta.Name)
+	span.SetAttributes(
+		Attr(AttrRequestType, meta.Name),
+		Attr(AttrHTTPMethod, meta.Method),
+		Attr(AttrHTTPRoute, meta.PathTemplate),
+	)
+	return ctx, span
+}
+
+// EndSpan records the error if it's not nil, then ends the span
+func EndSpan(span Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+
+		if code := StatusCode(err); code != 0 {
+			span.SetAttributes(Attr(AttrStatusCode, code))
+		}
+	}
+
+	span.End()
+}
+
+type noopSpan struct{}
+
+func (noopSpan) SetAttributes(...Attribute) {}
+
+func (noopSpan) AddEvent(string, ...Attribute) {}
+
+func (noopSpan) RecordError(error) {}
+
+func (noopSpan) End() {}
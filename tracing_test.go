@@ -0,0 +1,74 @@
+package requestgen
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testSpan struct {
+	name       string
+	attributes map[string]interface{}
+	events     []string
+	err        error
+	ended      bool
+}
+
+func (s *testSpan) SetAttributes(attributes ...Attribute) {
+	for _, attr := range attributes {
+		s.attributes[attr.Key] = attr.Value
+	}
+}
+
+func (s *testSpan) AddEvent(name string, attributes ...Attribute) {
+	s.events = append(s.events, name)
+}
+
+func (s *testSpan) RecordError(err error) {
+	s.err = err
+}
+
+func (s *testSpan) End() {
+	s.ended = true
+}
+
+type spanKey struct{}
+
+func TestStartSpan(t *testing.T) {
+	ctx := context.Background()
+
+	// no-op span without a tracer
+	newCtx, span := StartSpan(ctx, "noop")
+	assert.Equal(t, ctx, newCtx)
+	assert.Equal(t, noopSpan{}, span)
+
+	var spans []*testSpan
+	SetTracer(TracerFunc(func(ctx context.Context, name string) (context.Context, Span) {
+		span := &testSpan{name: name, attributes: map[string]interface{}{}}
+		spans = append(spans, span)
+		return context.WithValue(ctx, spanKey{}, span), span
+	}))
+	t.Cleanup(func() { SetTracer(nil) })
+
+	meta := &RequestMeta{Name: "GetOrderRequest", Method: "GET", PathTemplate: "/v1/orders/:orderID"}
+	newCtx, span = StartRequestSpan(ctx, meta)
+	assert.Equal(t, span, newCtx.Value(spanKey{}))
+
+	err := &ErrResponse{Response: &Response{Response: &http.Response{StatusCode: http.StatusNotFound}}}
+	EndSpan(span, err)
+
+	if assert.Len(t, spans, 1) {
+		assert.Equal(t, "GetOrderRequest", spans[0].name)
+		assert.Equal(t, map[string]interface{}{
+			AttrRequestType: "GetOrderRequest",
+			AttrHTTPMethod:  "GET",
+			AttrHTTPRoute:   "/v1/orders/:orderID",
+			AttrStatusCode:  http.StatusNotFound,
+		}, spans[0].attributes)
+		assert.True(t, errors.Is(spans[0].err, err))
+		assert.True(t, spans[0].ended)
+	}
+}